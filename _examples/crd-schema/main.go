@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/radeksimko/terraform-gen/docsgen"
+	"github.com/radeksimko/terraform-gen/openapi"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+// Usage: crd-schema crontab-crd.yaml v1 kubernetes_cron_tab
+//
+// Prints the schema of spec along with expander & flattener,
+// followed by the documentation
+func main() {
+	if len(os.Args) != 4 {
+		log.Fatalf("Usage: %s CRD VERSION RESOURCE_KEY", os.Args[0])
+	}
+	crdPath, version, resourceKey := os.Args[1], os.Args[2], os.Args[3]
+
+	crd, err := openapi.LoadCRDFile(crdPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s, err := crd.Schema(version)
+	if err != nil {
+		log.Fatal(err)
+	}
+	spec, ok := s.Properties["spec"]
+	if !ok {
+		log.Fatalf("%s has no spec", crd.Kind)
+	}
+
+	g := &openapi.Generator{}
+	fields, err := g.FieldsFromSchema(spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rendered, err := schemagen.RenderFields(fields)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, f := range fields {
+		fmt.Printf("%q: %s,\n", f.Name, rendered[f.Name])
+	}
+	for _, code := range g.SchemaFunctions() {
+		fmt.Printf("\n%s\n", code)
+	}
+
+	// spec of unstructured.Unstructured from/to ResourceData
+	helpers, err := g.HelpersFromSchema(crd.Kind+"Spec", spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, code := range helpers {
+		fmt.Printf("\n%s\n", code)
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	r := &docsgen.Resource{
+		ProviderKey:    "kubernetes",
+		ProviderName:   "Kubernetes",
+		ResourceKey:    resourceKey,
+		ResourceSlug:   strings.Replace(resourceKey, "_", "-", -1),
+		ResourceSchema: (&schemagen.Block{Fields: fields}).SDKResource(),
+	}
+	if err := r.GenerateResourceMarkdown(buf); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print("\n" + buf.String())
+}
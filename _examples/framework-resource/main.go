@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"text/template"
+
+	"github.com/radeksimko/terraform-gen/helpergen"
+	"github.com/radeksimko/terraform-gen/resourcegen"
+	"github.com/radeksimko/terraform-gen/schemagen"
+
+	api "k8s.io/kubernetes/pkg/api/v1"
+)
+
+func main() {
+	sg := &schemagen.SchemaGenerator{}
+	fields := schemagen.WithIDField(sg.FieldsFromStruct(&api.ConfigMap{}))
+
+	fs, err := schemagen.RenderFrameworkSchema(fields)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Models convert from/to api.ConfigMap directly (via toSDK & fromSDK)
+	hg := &helpergen.HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	models := hg.ModelsFromStruct(api.ConfigMap{})
+
+	buf := bytes.NewBuffer([]byte{})
+	err = schemaTemplate.Execute(buf, struct {
+		PkgName      string
+		VariableName string
+		Imports      []string
+		Schema       string
+		Models       map[string]string
+	}{
+		PkgName:      "kubernetes",
+		VariableName: "configMapSchema",
+		Imports:      append(fs.Imports, frameworkAttrImportPath, frameworkTypesImportPath),
+		Schema:       fs.Code(),
+		Models:       models,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	writeFormatted("config_map_schema.go", buf.Bytes())
+
+	r := &resourcegen.Resource{
+		PkgName:        "kubernetes",
+		ResourceKey:    "kubernetes_config_map",
+		Backend:        schemagen.Framework,
+		SDKType:        api.ConfigMap{},
+		SchemaVarName:  "configMapSchema",
+		ClientType:     "*kubernetes.Clientset",
+		CreateCall:     "conn.CoreV1().ConfigMaps({obj}.Namespace).Create(&{obj})",
+		ReadCall:       "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Get({metadata.0.name})",
+		UpdateCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Update(&{obj})",
+		DeleteCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Delete({metadata.0.name}, &api.DeleteOptions{})",
+		IsNotFoundFunc: "errors.IsNotFound",
+		IDFormat:       "{metadata.0.namespace}/{metadata.0.name}",
+	}
+	buf.Reset()
+	if err := r.GenerateResourceCode(buf); err != nil {
+		log.Fatal(err)
+	}
+	writeFormatted(r.Filename(), buf.Bytes())
+}
+
+const (
+	frameworkAttrImportPath  = "github.com/hashicorp/terraform-plugin-framework/attr"
+	frameworkTypesImportPath = "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func writeFormatted(filename string, code []byte) {
+	log.Printf("Generating %q...\n", filename)
+	formatted, err := format.Source(code)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filename, formatted, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Imports may repeat, which gofmt doesn't mind, but the compiler does
+var schemaTemplate = template.Must(template.New("schema").Funcs(template.FuncMap{
+	"unique": func(paths []string) []string {
+		seen := make(map[string]bool, len(paths))
+		unique := make([]string, 0, len(paths))
+		for _, p := range paths {
+			if !seen[p] {
+				seen[p] = true
+				unique = append(unique, p)
+			}
+		}
+		return unique
+	},
+}).Parse(`package {{.PkgName}}
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+{{- range unique .Imports}}
+	"{{.}}"
+{{- end}}
+)
+
+var {{.VariableName}} = {{.Schema}}
+{{range $name, $model := .Models}}
+{{ $model }}
+{{end}}`))
@@ -18,6 +18,7 @@ func main() {
 		ResourceKey:    "kubernetes_config_map",
 		ResourceSlug:   "kubernetes-config-map",
 		ResourceSchema: p.ResourcesMap["kubernetes_config_map"],
+		IDFormat:       "{metadata.0.namespace}/{metadata.0.name}",
 	}
 	r.GenerateResourceMarkdown(buf)
 	fmt.Print(buf.String())
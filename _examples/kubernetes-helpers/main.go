@@ -17,14 +17,23 @@ import (
 type helperGen struct {
 	Obj      interface{}
 	Filename string
+	// Round-trip test is only generated if this is set,
+	// it refers to the schema generated via kubernetes-schema
+	TestFilename  string
+	SchemaVarName string
+	// Plugin framework models are only generated if this is set
+	ModelsFilename string
 }
 
 func main() {
 	pkgName := "kubernetes"
 	schemas := []helperGen{
 		{
-			Obj:      api.PersistentVolumeSpec{},
-			Filename: "structure_persistent_volume_spec.go",
+			Obj:            api.PersistentVolumeSpec{},
+			Filename:       "structure_persistent_volume_spec.go",
+			TestFilename:   "structure_persistent_volume_spec_test.go",
+			SchemaVarName:  "persistentVolumeSpecSchema",
+			ModelsFilename: "model_persistent_volume_spec.go",
 		},
 	}
 
@@ -45,19 +54,60 @@ func main() {
 
 		flatteners := hg.FlattenersFromStruct(s.Obj)
 		expanders := hg.ExpandersFromStruct(s.Obj)
+		patchers := hg.PatchersFromStruct(s.Obj)
 
 		err = tpl.Execute(f, struct {
 			PkgName    string
 			Flatteners map[string]string
 			Expanders  map[string]string
+			Patchers   map[string]string
 		}{
 			PkgName:    pkgName,
 			Flatteners: flatteners,
 			Expanders:  expanders,
+			Patchers:   patchers,
 		})
 		if err != nil {
 			log.Fatal(err)
 		}
+
+		if s.TestFilename != "" {
+			log.Printf("Generating %q...\n", s.TestFilename)
+			tf, err := os.Create(s.TestFilename)
+			defer tf.Close()
+			if err != nil {
+				log.Fatal(err)
+			}
+			err = testTpl.Execute(tf, struct {
+				PkgName string
+				Tests   map[string]string
+			}{
+				PkgName: pkgName,
+				Tests:   hg.RoundTripTestsFromStruct(s.Obj, s.SchemaVarName),
+			})
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		if s.ModelsFilename != "" {
+			log.Printf("Generating %q...\n", s.ModelsFilename)
+			mf, err := os.Create(s.ModelsFilename)
+			defer mf.Close()
+			if err != nil {
+				log.Fatal(err)
+			}
+			err = modelsTpl.Execute(mf, struct {
+				PkgName string
+				Models  map[string]string
+			}{
+				PkgName: pkgName,
+				Models:  hg.ModelsFromStruct(s.Obj),
+			})
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
 	}
 }
 
@@ -162,4 +212,34 @@ var tpl = template.Must(template.New("pod").Parse(`package {{.PkgName}}
 {{range $name, $definition := .Expanders}}
 {{ $definition }}
 {{end}}
+
+// Patchers
+{{range $name, $definition := .Patchers}}
+{{ $definition }}
+{{end}}
+`))
+
+var testTpl = template.Must(template.New("round-trip-test").Parse(`package {{.PkgName}}
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+{{range $name, $definition := .Tests}}
+{{ $definition }}
+{{end}}
+`))
+
+var modelsTpl = template.Must(template.New("models").Parse(`package {{.PkgName}}
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+{{range $name, $definition := .Models}}
+{{ $definition }}
+{{end}}
 `))
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/resourcegen"
+	"github.com/radeksimko/terraform-gen/schemagen"
+	"github.com/radeksimko/terraform-gen/schemajson"
+
+	api "k8s.io/kubernetes/pkg/api/v1"
+)
+
+// Usage: kubernetes-resource [schema.json]
+//
+// schema.json (terraform providers schema -json) of the previous release
+// is compared with the generated schema, see GenerateStateUpgraderCode
+func main() {
+	buf := bytes.NewBuffer([]byte{})
+	r := &resourcegen.Resource{
+		PkgName:        "kubernetes",
+		ResourceKey:    "kubernetes_config_map",
+		SDKType:        api.ConfigMap{},
+		SchemaVarName:  "configMapSchema",
+		ClientType:     "*kubernetes.Clientset",
+		CreateCall:     "conn.CoreV1().ConfigMaps({obj}.Namespace).Create(&{obj})",
+		ReadCall:       "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Get({metadata.0.name})",
+		UpdateCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Update(&{obj})",
+		DeleteCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Delete({metadata.0.name}, &api.DeleteOptions{})",
+		IsNotFoundFunc: "errors.IsNotFound",
+		IDFormat:       "{metadata.0.namespace}/{metadata.0.name}",
+	}
+	if len(os.Args) > 1 {
+		prev, err := previousSchema(os.Args[1], r.ResourceKey)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sg := &schemagen.SchemaGenerator{}
+		r.PreviousSchema = prev
+		r.SchemaFields = sg.FieldsFromStruct(&api.ConfigMap{})
+	}
+
+	err := r.GenerateResourceCode(buf)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(buf.String())
+
+	if r.PreviousSchema != nil && r.SchemaVersion() > r.PreviousSchema.SchemaVersion {
+		buf.Reset()
+		if err := r.GenerateStateUpgraderCode(buf); err != nil {
+			panic(err)
+		}
+		fmt.Printf("\n// %s\n%s", r.StateUpgraderFilename(), buf.String())
+	}
+}
+
+func previousSchema(path, resourceKey string) (*schema.Resource, error) {
+	ps, err := schemajson.LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range ps.Schemas {
+		if s, ok := p.ResourceSchemas[resourceKey]; ok {
+			return s.SDKResource()
+		}
+	}
+	return nil, fmt.Errorf("%s not found in %s", resourceKey, path)
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/hashicorp/terraform/builtin/providers/kubernetes"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/schemajson"
+)
+
+// Same output as terraform providers schema -json, without building the provider
+func main() {
+	p := kubernetes.Provider().(*schema.Provider)
+	ps := schemajson.FromProvider("registry.terraform.io/hashicorp/kubernetes", p)
+	if err := ps.Write(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
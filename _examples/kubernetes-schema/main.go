@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"reflect"
+	"regexp"
 	"strings"
 	"text/template"
 
@@ -38,17 +39,23 @@ func main() {
 			log.Fatal(err)
 		}
 
-		sg := &schemagen.SchemaGenerator{DocsFunc: docsFunc, FilterFunc: filterFunc}
+		sg := &schemagen.SchemaGenerator{
+			DocsFunc:          docsFunc,
+			FilterFunc:        filterFunc,
+			SharedSchemaFuncs: true,
+		}
 		fields := sg.FromStruct(s.Obj)
 
 		err = podTemplate.Execute(f, struct {
 			PkgName      string
 			VariableName string
 			Fields       map[string]string
+			Functions    map[string]string
 		}{
 			PkgName:      pkgName,
 			VariableName: s.VariableName,
 			Fields:       fields,
+			Functions:    sg.SchemaFunctions(),
 		})
 		if err != nil {
 			log.Fatal(err)
@@ -144,9 +151,16 @@ func filterFunc(iface interface{}, sf *reflect.StructField, kind reflect.Kind, s
 		s.ForceNew = true
 	}
 
+	// Converted to the field's type by the generator
+	if m := defaultsRe.FindStringSubmatch(docs); m != nil && s.Optional {
+		s.Default = strings.Trim(m[1], `"'`)
+	}
+
 	return kind, true
 }
 
+var defaultsRe = regexp.MustCompile(`Defaults to ([^\s,]+)\.`)
+
 func getSwaggerDocs(iface interface{}, sf *reflect.StructField, jsonName string) (string, error) {
 	structType := reflect.TypeOf(iface)
 	if structType.Kind() == reflect.Ptr {
@@ -177,4 +191,7 @@ var {{.VariableName}} = map[string]*schema.Schema{
 {{range $name, $schema := .Fields}}
 	"{{ $name }}": {{ $schema }},{{end}}
 }
+{{range $name, $definition := .Functions}}
+{{ $definition }}
+{{end}}
 `))
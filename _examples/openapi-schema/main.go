@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/radeksimko/terraform-gen/openapi"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+// Usage: openapi-schema petstore.yaml Pet pet_schema.go petSchema
+func main() {
+	if len(os.Args) != 5 {
+		log.Fatalf("Usage: %s SPEC DEFINITION FILENAME VARIABLE", os.Args[0])
+	}
+	specPath, definition, filename, varName := os.Args[1], os.Args[2], os.Args[3], os.Args[4]
+
+	spec, err := openapi.LoadFile(specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	g := &openapi.Generator{Spec: spec}
+	fields, err := g.FieldsFromDefinition(definition)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rendered, err := schemagen.RenderFields(fields)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Generating %q...\n", filename)
+	f, err := os.Create(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	sf := &schemagen.SchemaFile{
+		PkgName:      "petstore",
+		VariableName: varName,
+		Fields:       rendered,
+		Functions:    g.SchemaFunctions(),
+	}
+	if err := sf.Write(f); err != nil {
+		log.Fatal(err)
+	}
+}
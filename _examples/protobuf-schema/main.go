@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/radeksimko/terraform-gen/protobuf"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+// Usage: protobuf-schema compute.pb acme.compute.v1.Instance instance_schema.go instanceSchema
+//
+// compute.pb is produced via
+// protoc --include_imports --include_source_info --descriptor_set_out=compute.pb acme/compute/v1/*.proto
+func main() {
+	if len(os.Args) != 5 {
+		log.Fatalf("Usage: %s DESCRIPTOR_SET MESSAGE FILENAME VARIABLE", os.Args[0])
+	}
+	setPath, message, filename, varName := os.Args[1], os.Args[2], os.Args[3], os.Args[4]
+
+	files, err := protobuf.LoadFile(setPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	g := &protobuf.Generator{Files: files}
+	fields, err := g.FieldsFromMessage(message)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rendered, err := schemagen.RenderFields(fields)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Generating %q...\n", filename)
+	f, err := os.Create(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	sf := &schemagen.SchemaFile{
+		PkgName:      "compute",
+		VariableName: varName,
+		Fields:       rendered,
+		Functions:    g.SchemaFunctions(),
+	}
+	if err := sf.Write(f); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/radeksimko/terraform-gen/docsgen"
+	"github.com/radeksimko/terraform-gen/schemajson"
+)
+
+// Usage: schema-json-docs schema.json Kubernetes website/docs
+//
+// schema.json is produced via terraform providers schema -json
+func main() {
+	if len(os.Args) != 4 {
+		log.Fatalf("Usage: %s SCHEMA_JSON PROVIDER_NAME OUTPUT_DIR", os.Args[0])
+	}
+	schemaPath, providerName, outputDir := os.Args[1], os.Args[2], os.Args[3]
+
+	ps, err := schemajson.LoadFile(schemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	resources, err := docsgen.ResourcesFromSchemaJSON(ps, "", providerName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, r := range resources {
+		dir := "r"
+		if r.IsDataSource {
+			dir = "d"
+		}
+		name := strings.TrimPrefix(r.ResourceKey, r.ProviderKey+"_")
+		filename := filepath.Join(outputDir, dir, name+".html.markdown")
+
+		log.Printf("Generating %q...\n", filename)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			log.Fatal(err)
+		}
+		f, err := os.Create(filename)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = r.GenerateResourceMarkdown(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package docsgen
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"strings"
 	"text/template"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
 )
 
 type Resource struct {
@@ -16,41 +18,55 @@ type Resource struct {
 	ResourceKey    string
 	ResourceSlug   string
 	ResourceSchema *schema.Resource
+
+	// e.g. {metadata.0.namespace}/{metadata.0.name}
+	IDFormat string
+
+	IsDataSource bool
 }
 
 func (r *Resource) GenerateResourceMarkdown(wr io.Writer) error {
-	rd := r.resourceDocsFromSchema(r.ResourceSchema, nil, false)
+	rd := r.resourceDocsFromSchema(r.ResourceSchema)
+	if r.IDFormat != "" {
+		id, err := u.ParseIDFormat(r.IDFormat)
+		if err != nil {
+			return err
+		}
+		rd.ID = &ImportID{
+			Description: importIDDescription(id),
+			Example:     id.Example(),
+		}
+	}
 	return resourceDocsTemplate.Execute(wr, rd)
 }
 
-func (r *Resource) resourceDocsFromSchema(res *schema.Resource, docs *ResourceDocs, isNested bool) *ResourceDocs {
-	if docs == nil {
-		docs = &ResourceDocs{
-			ProviderKey:        r.ProviderKey,
-			ProviderName:       r.ProviderName,
-			ResourceKey:        r.ResourceKey,
-			ResourceSlug:       r.ResourceSlug,
-			MarkdownHeaderFunc: markdownHeader,
-			Fields:             make(map[string]*schema.Schema),
-			NestedFields:       make(map[string]map[string]*schema.Schema),
-		}
+func (r *Resource) resourceDocsFromSchema(res *schema.Resource) *ResourceDocs {
+	docs := &ResourceDocs{
+		ProviderKey:        r.ProviderKey,
+		ProviderName:       r.ProviderName,
+		ResourceKey:        r.ResourceKey,
+		ResourceSlug:       r.ResourceSlug,
+		IsDataSource:       r.IsDataSource,
+		MarkdownHeaderFunc: markdownHeader,
+		Fields:             make(map[string]*schema.Schema),
+		NestedFields:       make(map[string]map[string]*schema.Schema),
 	}
 
-	for name, s := range res.Schema {
+	u.WalkResource(res, func(path []string, s *schema.Schema) {
+		name := path[len(path)-1]
 		if v, isResource := s.Elem.(*schema.Resource); isResource {
-			docs.NestedFields[name] = v.Schema
 			log.Printf("Processing nested field: %q", name)
-			r.resourceDocsFromSchema(v, docs, true)
+			docs.NestedFields[name] = v.Schema
 		}
 		if _, isSchema := s.Elem.(*schema.Schema); isSchema {
 			log.Printf("Nested Schema is not implemented (yet) - SKIPPING %q", name)
 		}
 
-		if !isNested {
+		if len(path) == 1 {
 			log.Printf("Processing primitive field: %q", name)
 			docs.Fields[name] = s
 		}
-	}
+	})
 
 	return docs
 }
@@ -59,18 +75,43 @@ func markdownHeader(header string) string {
 	return strings.Replace(header, "_", "\\_", 0)
 }
 
+// e.g. the `namespace` and `name` separated by `/`
+func importIDDescription(id *u.IDFormat) string {
+	names := make([]string, len(id.Parts))
+	for i, p := range id.Parts {
+		names[i] = "`" + p.Name + "`"
+	}
+	if len(names) == 1 {
+		return "the " + names[0]
+	}
+	last := len(names) - 1
+	return fmt.Sprintf("the %s and %s separated by `%s`",
+		strings.Join(names[:last], ", "), names[last], id.Separator)
+}
+
 type ResourceDocs struct {
 	ProviderKey  string
 	ProviderName string
 	ResourceKey  string
 	ResourceSlug string
+	IsDataSource bool
 
 	Fields       map[string]*schema.Schema
 	NestedFields map[string]map[string]*schema.Schema
 
+	ID *ImportID
+
 	MarkdownHeaderFunc func(s string) string
 }
 
+// ImportID is the ID accepted by terraform import
+type ImportID struct {
+	// e.g. the `namespace` and `name` separated by `/`
+	Description string
+	// e.g. namespace/name
+	Example string
+}
+
 var resourceDocsTemplate = template.Must(template.New("resource-docs").Parse(`
 ---
 layout: "{{.ProviderKey}}"
@@ -80,7 +121,7 @@ description: |-
   TODO
 ---
 
-# {{call .MarkdownHeaderFunc .ResourceKey}}
+# {{if .IsDataSource}}Data Source: {{end}}{{call .MarkdownHeaderFunc .ResourceKey}}
 
 TODO
 
@@ -88,7 +129,7 @@ TODO
 ## Example Usage
 
 ` + "```" + `
-resource "{{.ResourceKey}}" "example" {
+{{if .IsDataSource}}data{{else}}resource{{end}} "{{.ResourceKey}}" "example" {
   // TODO
 }
 ` + "```" + `
@@ -128,12 +169,24 @@ exported:
 {{range $key, $schema := .Fields}}{{if and $schema.Computed (not $schema.Optional)}}
 * ` + "`{{ $key }}`" + ` - {{ $schema.Description }}
 {{end}}{{end}}
+{{- if not .IsDataSource}}
 ## Import
 
-{{.ResourceKey}} can be imported using the , e.g.
+{{- if .ID}}
+
+{{.ResourceKey}} can be imported using {{.ID.Description}}, e.g.
+
+` + "```" + `
+$ terraform import {{.ResourceKey}}.example {{.ID.Example}}
+` + "```" + `
+{{- else}}
+
+{{.ResourceKey}} can be imported using its ID, e.g.
 
 ` + "```" + `
 $ terraform import {{.ResourceKey}}.example ...
 ` + "```" + `
+{{- end}}
+{{- end}}
 
 `))
@@ -248,7 +248,7 @@ exported:
 
 ## Import
 
-cattle_cow can be imported using the , e.g.
+cattle_cow can be imported using its ID, e.g.
 
 ` + "```" + `
 $ terraform import cattle_cow.example ...
@@ -309,7 +309,7 @@ exported:
 
 ## Import
 
-cattle_cow can be imported using the , e.g.
+cattle_cow can be imported using its ID, e.g.
 
 ` + "```" + `
 $ terraform import cattle_cow.example ...
@@ -394,10 +394,85 @@ exported:
 
 ## Import
 
-cattle_cow can be imported using the , e.g.
+cattle_cow can be imported using its ID, e.g.
 
 ` + "```" + `
 $ terraform import cattle_cow.example ...
 ` + "```" + `
 
 `
+
+func TestGenerateResourceMarkdown_importID(t *testing.T) {
+	resource := schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the cow.",
+				Required:    true,
+			},
+		},
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	r := &Resource{
+		ProviderKey:    "cattle",
+		ProviderName:   "Cattle",
+		ResourceKey:    "cattle_cow",
+		ResourceSlug:   "cattle-cow",
+		ResourceSchema: &resource,
+		IDFormat:       "{herd}/{name}",
+	}
+	err := r.GenerateResourceMarkdown(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	expectedOutput := markdown_import_id_output
+	if output != expectedOutput {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", expectedOutput, output)
+	}
+}
+
+var markdown_import_id_output = `
+---
+layout: "cattle"
+page_title: "Cattle: cattle_cow"
+sidebar_current: "docs-cattle-cow"
+description: |-
+  TODO
+---
+
+# cattle_cow
+
+TODO
+
+
+## Example Usage
+
+` + "```" + `
+resource "cattle_cow" "example" {
+  // TODO
+}
+` + "```" + `
+
+## Argument Reference
+
+The following arguments are supported:
+
+* ` + "`name`" + ` - (Required) Name of the cow.
+
+## Attributes Reference
+
+In addition to the arguments listed above, the following computed attributes are
+exported:
+
+## Import
+
+cattle_cow can be imported using the ` + "`herd`" + ` and ` + "`name`" + ` separated by ` + "`/`" + `, e.g.
+
+` + "```" + `
+$ terraform import cattle_cow.example herd/name
+` + "```" + `
+
+`
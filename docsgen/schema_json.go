@@ -0,0 +1,78 @@
+package docsgen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/schemajson"
+)
+
+// ResourcesFromSchemaJSON prepares docs of all resources and data sources
+// (sorted by key) of the provider found in terraform providers schema -json
+// output, source may be omitted if there's only one provider,
+// e.g. registry.terraform.io/hashicorp/kubernetes
+func ResourcesFromSchemaJSON(ps *schemajson.ProviderSchemas, source, providerName string) ([]*Resource, error) {
+	if source == "" && len(ps.Schemas) == 1 {
+		for s := range ps.Schemas {
+			source = s
+		}
+	}
+	provider, ok := ps.Schemas[source]
+	if !ok {
+		return nil, fmt.Errorf("Provider %q not found", source)
+	}
+	providerKey := source[strings.LastIndex(source, "/")+1:]
+
+	resources := make([]*Resource, 0, len(provider.ResourceSchemas)+len(provider.DataSourceSchemas))
+	for _, key := range sortedKeys(provider.ResourceSchemas) {
+		res, err := sdkResource(provider.ResourceSchemas[key])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", key, err)
+		}
+		resources = append(resources, &Resource{
+			ProviderKey:    providerKey,
+			ProviderName:   providerName,
+			ResourceKey:    key,
+			ResourceSlug:   strings.Replace(key, "_", "-", -1),
+			ResourceSchema: res,
+		})
+	}
+	for _, key := range sortedKeys(provider.DataSourceSchemas) {
+		res, err := sdkResource(provider.DataSourceSchemas[key])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", key, err)
+		}
+		resources = append(resources, &Resource{
+			ProviderKey:    providerKey,
+			ProviderName:   providerName,
+			ResourceKey:    key,
+			ResourceSlug:   "datasource-" + strings.Replace(key, "_", "-", -1),
+			ResourceSchema: res,
+			IsDataSource:   true,
+		})
+	}
+	return resources, nil
+}
+
+// The id attribute implied by the SDK isn't documented
+func sdkResource(s *schemajson.Schema) (*schema.Resource, error) {
+	res, err := s.SDKResource()
+	if err != nil {
+		return nil, err
+	}
+	if id, ok := res.Schema["id"]; ok && id.Optional && id.Computed && id.Description == "" {
+		delete(res.Schema, "id")
+	}
+	return res, nil
+}
+
+func sortedKeys(m map[string]*schemajson.Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,133 @@
+package docsgen
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/radeksimko/terraform-gen/schemajson"
+)
+
+const cattleSchemaJSON = `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/acme/cattle": {
+      "resource_schemas": {
+        "cattle_cow": {
+          "version": 0,
+          "block": {
+            "attributes": {
+              "id": {"type": "string", "optional": true, "computed": true},
+              "name": {"type": "string", "description": "Name of the cow", "required": true}
+            }
+          }
+        }
+      },
+      "data_source_schemas": {
+        "cattle_herd": {
+          "version": 0,
+          "block": {
+            "attributes": {
+              "id": {"type": "string", "optional": true, "computed": true},
+              "size": {"type": "number", "description": "Number of cows", "computed": true}
+            },
+            "block_types": {
+              "filter": {
+                "nesting_mode": "list",
+                "block": {
+                  "attributes": {
+                    "breed": {"type": "string", "description": "Breed of cows", "optional": true}
+                  }
+                },
+                "min_items": 1
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestResourcesFromSchemaJSON(t *testing.T) {
+	ps, err := schemajson.Parse([]byte(cattleSchemaJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources, err := ResourcesFromSchemaJSON(ps, "", "Cattle")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slugs := make([]string, len(resources))
+	for i, r := range resources {
+		slugs[i] = r.ResourceSlug
+	}
+	expectedSlugs := []string{"cattle-cow", "datasource-cattle-herd"}
+	if !reflect.DeepEqual(slugs, expectedSlugs) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedSlugs, slugs)
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err = resources[1].GenerateResourceMarkdown(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	expectedOutput := markdown_dataSource_output
+	if output != expectedOutput {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", expectedOutput, output)
+	}
+}
+
+const markdown_dataSource_output = `
+---
+layout: "cattle"
+page_title: "Cattle: cattle_herd"
+sidebar_current: "docs-datasource-cattle-herd"
+description: |-
+  TODO
+---
+
+# Data Source: cattle_herd
+
+TODO
+
+
+## Example Usage
+
+` + "```" + `
+data "cattle_herd" "example" {
+  // TODO
+}
+` + "```" + `
+
+## Argument Reference
+
+The following arguments are supported:
+
+* ` + "`filter`" + ` - (Required) 
+
+## Nested Blocks
+
+### ` + "`filter`" + `
+
+#### Arguments
+
+* ` + "`breed`" + ` - (Optional) Breed of cows
+
+#### Attributes
+
+
+
+
+## Attributes Reference
+
+In addition to the arguments listed above, the following computed attributes are
+exported:
+
+* ` + "`size`" + ` - Number of cows
+
+
+`
@@ -20,33 +20,32 @@ func (hg *HelperGenerator) generateExpandersFromStruct(iface interface{}) string
 	t := reflect.TypeOf(iface)
 	rawType := getRawType(t)
 
-	funcName := expanderFuncNameFromType(t)
+	hg.pushType(t)
+	defer hg.popType()
+
+	funcName := hg.levelFuncName(expanderFuncNameFromType(t))
 	funcBody := hg.expanderBodyBeginning(t)
 
-	// Inline fields (typically those we never expect to be empty)
+	// Only embedded structs are initialized in the declaration,
+	// so that their fields can be assigned afterwards
+	fields := hg.structFields(rawType, iface)
 	funcBody += hg.inlineExpanderDeclarationBeginning(t)
-	for i := 0; i < rawType.NumField(); i++ {
-		sf := rawType.Field(i)
-		body, err := hg.inlineExpanderField(sf.Name, sf.Type, iface, &sf)
-		if err != nil {
-			log.Printf("Skipping %s (inline): %s", sf.Name, err)
-			continue
-		}
-		funcBody += body
+	if inits := hg.embeddedStructInits(fields); inits != "" {
+		funcBody += "\n" + inits
 	}
 	funcBody += hg.inlineExpanderDeclarationEnd(t)
 
-	// Outline fields (typically optional)
-	for i := 0; i < rawType.NumField(); i++ {
-		sf := rawType.Field(i)
-		body, err := hg.outlineExpanderField(sf.Name, sf.Type, iface, &sf)
-		if err != nil {
-			log.Printf("Skipping %s (outline): %s", sf.Name, err)
-			continue
-		}
-		funcBody += body
+	target := "obj"
+	if t.Kind() == reflect.Slice {
+		target = "obj[i]"
 	}
 
+	// Inline fields (typically those we never expect to be empty)
+	funcBody += hg.inlineExpanderFields(fields, target, "")
+
+	// Outline fields (typically optional)
+	funcBody += hg.outlineExpanderFields(fields, target, "")
+
 	funcBody += hg.expanderBodyEnd(t)
 	args := "l" + " []interface{}"
 	hg.declarations[funcName] = &FunctionDeclaration{
@@ -60,6 +59,68 @@ func (hg *HelperGenerator) generateExpandersFromStruct(iface interface{}) string
 	return funcName
 }
 
+func (hg *HelperGenerator) inlineExpanderFields(fields []*structField, target, prefix string) string {
+	body := ""
+	for _, f := range fields {
+		if f.isPromoted {
+			body += hg.inlineExpanderFields(f.promoted, target, prefix+f.Name+".")
+			continue
+		}
+		fieldBody, err := hg.inlineExpanderField(target, prefix+f.Name, f.Type, f.iface, &f.StructField)
+		if err != nil {
+			log.Printf("Skipping %s (inline): %s", f.Name, err)
+			body += skippedFieldCode(f.Name, err)
+			continue
+		}
+		body += fieldBody
+	}
+	return body
+}
+
+func (hg *HelperGenerator) outlineExpanderFields(fields []*structField, target, prefix string) string {
+	body := ""
+	for _, f := range fields {
+		if f.isPromoted {
+			body += hg.outlineExpanderFields(f.promoted, target, prefix+f.Name+".")
+			continue
+		}
+		fieldBody, err := hg.outlineExpanderField(target, prefix+f.Name, f.Type, f.iface, &f.StructField)
+		if err != nil {
+			log.Printf("Skipping %s (outline): %s", f.Name, err)
+			body += skippedFieldCode(f.Name, err)
+			continue
+		}
+		body += fieldBody
+	}
+	return body
+}
+
+// Embedded structs are always initialized, so promoted fields
+// can be safely assigned afterwards, even if it's a pointer
+func (hg *HelperGenerator) embeddedStructInits(fields []*structField) string {
+	body := ""
+	for _, f := range fields {
+		if !f.isPromoted {
+			continue
+		}
+		ptr := ""
+		structType := f.Type
+		if structType.Kind() == reflect.Ptr {
+			ptr = "&"
+			structType = structType.Elem()
+		}
+		inits := hg.embeddedStructInits(f.promoted)
+		if ptr == "" && inits == "" {
+			continue
+		}
+		if inits != "" {
+			inits = "\n" + inits
+		}
+		body += fmt.Sprintf("%s: %s%s{%s},\n", f.Name, ptr, structType.String(), inits)
+	}
+	return body
+}
+
 func (hg *HelperGenerator) inlineExpanderDeclarationBeginning(t reflect.Type) string {
 	if t.Kind() == reflect.Slice {
 		ptr := ""
@@ -68,7 +129,7 @@ func (hg *HelperGenerator) inlineExpanderDeclarationBeginning(t reflect.Type) st
 			ptr = "&"
 			t = t.Elem()
 		}
-		return `obj[i] = ` + ptr + t.String() + "{\n"
+		return `obj[i] = ` + ptr + t.String() + "{"
 	}
 
 	ptr := ""
@@ -77,14 +138,15 @@ func (hg *HelperGenerator) inlineExpanderDeclarationBeginning(t reflect.Type) st
 		t = t.Elem()
 	}
 
-	return "obj := " + ptr + t.String() + "{\n"
+	return "obj := " + ptr + t.String() + "{"
 }
 
 func (hg *HelperGenerator) inlineExpanderDeclarationEnd(t reflect.Type) string {
 	return "}\n"
 }
 
-func (hg *HelperGenerator) inlineExpanderField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField) (string, error) {
+// Inline fields are assigned whenever present, even if zero
+func (hg *HelperGenerator) inlineExpanderField(target, sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField) (string, error) {
 	rawType := u.DereferencePtrType(sfType)
 	kind := rawType.Kind()
 	s := &schema.Schema{}
@@ -97,20 +159,19 @@ func (hg *HelperGenerator) inlineExpanderField(sfName string, sfType reflect.Typ
 		}
 	}
 
-	wrapperFunc, value, err := hg.expanderFieldValue(kind, sf, sfName, sfType)
-	if err != nil {
-		return "", err
-	}
-	leftSide := sf.Name
-
-	if wrapperFunc != "" {
-		value = fmt.Sprintf("%s(%s)", wrapperFunc, value)
+	// Unset pointers are left nil (like d.GetOk)
+	condition := "ok"
+	if sfType.Kind() == reflect.Ptr {
+		if nonZero := hg.nonZeroCondition(kind, sfType); nonZero != "" {
+			condition += " && " + nonZero
+		}
 	}
-
-	return fmt.Sprintf("%s: %s,\n", leftSide, value), nil
+	return hg.expanderFieldAssignment(target, sfName, kind, sfType, sf, condition)
 }
 
-func (hg *HelperGenerator) outlineExpanderField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField) (string, error) {
+// Outline fields are only assigned if set to a non-zero value (like d.GetOk),
+// so that pointers are left nil otherwise
+func (hg *HelperGenerator) outlineExpanderField(target, sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField) (string, error) {
 	rawType := u.DereferencePtrType(sfType)
 	kind := rawType.Kind()
 	s := &schema.Schema{}
@@ -123,71 +184,109 @@ func (hg *HelperGenerator) outlineExpanderField(sfName string, sfType reflect.Ty
 		}
 	}
 
-	wrapperFunc, value, err := hg.expanderFieldValue(kind, sf, sfName, sfType)
+	condition := "ok"
+	if nonZero := hg.nonZeroCondition(kind, sfType); nonZero != "" {
+		condition += " && " + nonZero
+	}
+	return hg.expanderFieldAssignment(target, sfName, kind, sfType, sf, condition)
+}
+
+// Missing key or nil value never passes the type assertion
+func (hg *HelperGenerator) expanderFieldAssignment(target, sfName string, kind reflect.Kind, sfType reflect.Type, sf *reflect.StructField, condition string) (string, error) {
+	assignedValue, value, err := hg.expanderFieldValue(kind, sf, sfName, sfType)
 	if err != nil {
 		return "", err
 	}
-	leftSide := sf.Name
-	assignedValue := "v"
-	if wrapperFunc != "" {
-		assignedValue = fmt.Sprintf("%s(v)", wrapperFunc)
+
+	return fmt.Sprintf(`if v, ok := %s; %s {
+%s.%s = %s
+}
+`, value, condition, target, sfName, assignedValue), nil
+}
+
+func (hg *HelperGenerator) nonZeroCondition(kind reflect.Kind, sfType reflect.Type) string {
+	if conv, _ := hg.fieldConversion(kind, sfType); conv != u.NoConversion {
+		return `v != ""`
 	}
 
-	lengthCondition := ""
 	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "v != 0"
+	case reflect.String:
+		return `v != ""`
+	case reflect.Bool:
+		return "v"
 	case reflect.Struct, reflect.Slice, reflect.Map:
-		lengthCondition = " && len(v) > 0"
+		return "len(v) > 0"
 	}
-
-	return fmt.Sprintf(`if v, ok := %s; ok%s {
-%s.%s = %s
-}
-`, value, lengthCondition, "obj", leftSide, assignedValue), nil
+	return ""
 }
 
+// Value assigned (as an expression of v) & the type assertion v comes from
 func (hg *HelperGenerator) expanderFieldValue(kind reflect.Kind, sf *reflect.StructField, sfName string, sfType reflect.Type) (string, string, error) {
+	conv, err := hg.fieldConversion(kind, sfType)
+	if err != nil {
+		return "", "", err
+	}
+	if conv != u.NoConversion {
+		funcName := hg.conversionExpanderForType(conv, sfType)
+		return funcName + "(v)", fmt.Sprintf("%s[%q].(string)", hg.InputVarName, u.Underscore(sf.Name)), nil
+	}
+
 	switch kind {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
-		castType := sfType.String()
+		// The SDK only stores int, float64, string or bool
+		rawType := u.DereferencePtrType(sfType)
+		schemaType := schemaGoType(kind)
+		value := fmt.Sprintf("%s[%q].(%s)", hg.InputVarName, u.Underscore(sf.Name), schemaType)
+		assignedValue := "v"
+		if rawType.String() != schemaType {
+			if rawType.Kind() != kind {
+				break
+			}
+			assignedValue = fmt.Sprintf("%s(v)", rawType.String())
+		}
 
 		if sfType.Kind() == reflect.Ptr {
-			castType = sfType.Elem().String()
-			firstLetter := strings.ToUpper(string(castType[0]))
-			ptrHelperFunc := "ptrTo" + firstLetter + castType[1:]
-			return ptrHelperFunc, fmt.Sprintf("%s[%q].(%v)", hg.InputVarName, u.Underscore(sf.Name), castType), nil
+			castType := rawType.String()
+			ptrHelperFunc := "ptrTo" + strings.ToUpper(string(castType[0])) + castType[1:]
+			assignedValue = fmt.Sprintf("%s(%s)", ptrHelperFunc, assignedValue)
 		}
-
-		return "", fmt.Sprintf("%s[%q].(%v)", hg.InputVarName, u.Underscore(sf.Name), castType), nil
+		return assignedValue, value, nil
 	case reflect.Map:
 		// TODO: map[string]*string
 		// TODO: map[string]int
 		// TODO: map[string]bool
 		// TODO: map[string]float
-		return "expandStringMap", fmt.Sprintf("%s[%q].(map[string]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
+		return "expandStringMap(v)", fmt.Sprintf("%s[%q].(map[string]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
 	case reflect.Slice:
-		// TODO: s.Type == TypeSet
+		// Slices are TypeSet, unless changed by a filter
+		hg.declarations[listValueFunc.FuncName] = listValueFunc
+		list := fmt.Sprintf("listValue(%s[%q])", hg.InputVarName, u.Underscore(sf.Name))
 		sliceOf := sfType.Elem()
 		switch sliceOf.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 			reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
 			// Slice of primitive data types
 			funcName := hg.primitiveSliceExpanderForType(sliceOf, sfType)
-			return funcName, fmt.Sprintf("%s[%q].([]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
+			return funcName + "(v)", list, nil
 		case reflect.Ptr:
 			ptrTo := sliceOf.Elem()
 			funcName := hg.primitiveSliceExpanderForType(ptrTo, sfType)
-			return funcName, fmt.Sprintf("%s[%q].([]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
+			return funcName + "(v)", list, nil
 		case reflect.Struct:
 			iface := reflect.New(sfType).Elem().Interface()
 			funcName := hg.generateExpandersFromStruct(iface)
-			return funcName, fmt.Sprintf("%s[%q].([]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
+			return funcName + "(v)", list, nil
 		}
 	case reflect.Struct:
 		iface := reflect.New(sfType).Elem().Interface()
 		funcName := hg.generateExpandersFromStruct(iface)
-		return funcName, fmt.Sprintf("%s[%q].([]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
+		return funcName + "(v)", fmt.Sprintf("%s[%q].([]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
 	}
 
 	f := fmt.Sprintf("%s %s\n", sfName, sfType.String())
@@ -202,9 +301,7 @@ return ` + t.String() + `{}
 }
 obj := make(` + t.String() + `, len(l), len(l))
 for i, n := range l {
-cfg := n.(map[string]interface{})
-`
-		hg.mapVarName = "cfg"
+` + hg.InputVarName + " := n.(map[string]interface{})\n"
 		return code
 	}
 
@@ -214,8 +311,14 @@ cfg := n.(map[string]interface{})
 		t = t.Elem()
 	}
 
+	// Unset block leaves the pointer nil
+	emptyValue := t.String() + "{}"
+	if ptr != "" {
+		emptyValue = "nil"
+	}
+
 	code += `if len(l) == 0 || l[0] == nil {
-return ` + ptr + t.String() + `{}
+return ` + emptyValue + `
 }
 ` + hg.InputVarName + " := l[0].(map[string]interface{})\n"
 
@@ -232,6 +335,9 @@ func (hg *HelperGenerator) expanderBodyEnd(t reflect.Type) string {
 
 func (hg *HelperGenerator) primitiveSliceExpanderForType(t reflect.Type, sfType reflect.Type) string {
 	sliceOf := sfType.Elem()
+	if sliceOf.Kind() == t.Kind() && t.Kind() != reflect.Struct && t.String() != schemaGoType(t.Kind()) {
+		return hg.interfaceExpanderForType(sfType)
+	}
 	switch t.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		if sliceOf.Kind() == reflect.Ptr {
@@ -260,9 +366,43 @@ func (hg *HelperGenerator) primitiveSliceExpanderForType(t reflect.Type, sfType
 	return ""
 }
 
+// Elements are read as the types the SDK stores (e.g. int instead of int32)
+func (hg *HelperGenerator) interfaceExpanderForType(t reflect.Type) string {
+	elemName := t.Elem().Name()
+	elemName = strings.ToUpper(elemName[:1]) + elemName[1:]
+	funcName := "expandSliceOf" + elemName
+	hg.declarations[funcName] = &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "l []interface{}",
+		Outputs:   t.String(),
+		FuncBody: `s := make(` + t.String() + `, len(l))
+for i, v := range l {
+s[i] = ` + t.Elem().String() + `(v.(` + schemaGoType(t.Elem().Kind()) + `))
+}
+return s`,
+	}
+	return funcName
+}
+
+func ExpanderFuncName(iface interface{}) string {
+	return expanderFuncNameFromType(reflect.TypeOf(iface))
+}
+
 func expanderFuncNameFromType(t reflect.Type) string {
 	// pkg.TypeName
 	parts := strings.Split(t.String(), ".")
 	rawTypeName := parts[1]
 	return "expand" + rawTypeName
 }
+
+var listValueFunc = &FunctionDeclaration{
+	FuncName:  "listValue",
+	Arguments: "v interface{}",
+	Outputs:   "([]interface{}, bool)",
+	FuncBody: `if s, ok := v.(*schema.Set); ok {
+return s.List(), true
+}
+l, ok := v.([]interface{})
+return l, ok`,
+}
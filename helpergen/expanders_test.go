@@ -3,11 +3,13 @@ package helpergen
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
 func TestExpanderFromStruct_primitives(t *testing.T) {
+	type RestartPolicy string
 	type SimpleStruct struct {
 		MyInt     int
 		MyInt8    int8
@@ -21,6 +23,7 @@ func TestExpanderFromStruct_primitives(t *testing.T) {
 		MyFloat64 float64
 		MyString  string
 		MyBool    bool
+		MyPolicy  RestartPolicy
 	}
 	hg := &HelperGenerator{
 		InputVarName:  "cfg",
@@ -34,19 +37,45 @@ if len(l) == 0 || l[0] == nil {
 return helpergen.SimpleStruct{}
 }
 cfg := l[0].(map[string]interface{})
-obj := helpergen.SimpleStruct{
-MyInt: cfg["my_int"].(int),
-MyInt8: cfg["my_int8"].(int8),
-MyInt16: cfg["my_int16"].(int16),
-MyInt32: cfg["my_int32"].(int32),
-MyInt64: cfg["my_int64"].(int64),
-MyUInt: cfg["my_u_int"].(uint),
-MyUInt32: cfg["my_u_int32"].(uint32),
-MyUInt64: cfg["my_u_int64"].(uint64),
-MyFloat32: cfg["my_float32"].(float32),
-MyFloat64: cfg["my_float64"].(float64),
-MyString: cfg["my_string"].(string),
-MyBool: cfg["my_bool"].(bool),
+obj := helpergen.SimpleStruct{}
+if v, ok := cfg["my_int"].(int); ok {
+obj.MyInt = v
+}
+if v, ok := cfg["my_int8"].(int); ok {
+obj.MyInt8 = int8(v)
+}
+if v, ok := cfg["my_int16"].(int); ok {
+obj.MyInt16 = int16(v)
+}
+if v, ok := cfg["my_int32"].(int); ok {
+obj.MyInt32 = int32(v)
+}
+if v, ok := cfg["my_int64"].(int); ok {
+obj.MyInt64 = int64(v)
+}
+if v, ok := cfg["my_u_int"].(int); ok {
+obj.MyUInt = uint(v)
+}
+if v, ok := cfg["my_u_int32"].(int); ok {
+obj.MyUInt32 = uint32(v)
+}
+if v, ok := cfg["my_u_int64"].(int); ok {
+obj.MyUInt64 = uint64(v)
+}
+if v, ok := cfg["my_float32"].(float64); ok {
+obj.MyFloat32 = float32(v)
+}
+if v, ok := cfg["my_float64"].(float64); ok {
+obj.MyFloat64 = v
+}
+if v, ok := cfg["my_string"].(string); ok {
+obj.MyString = v
+}
+if v, ok := cfg["my_bool"].(bool); ok {
+obj.MyBool = v
+}
+if v, ok := cfg["my_policy"].(string); ok {
+obj.MyPolicy = helpergen.RestartPolicy(v)
 }
 return obj
 }`,
@@ -77,19 +106,36 @@ func TestExpanderFromPtrToStruct_primitives(t *testing.T) {
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) *helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
-return &helpergen.SimpleStruct{}
+return nil
 }
 cfg := l[0].(map[string]interface{})
-obj := &helpergen.SimpleStruct{
-MyInt: cfg["my_int"].(int),
-MyInt8: cfg["my_int8"].(int8),
-MyInt16: cfg["my_int16"].(int16),
-MyInt32: cfg["my_int32"].(int32),
-MyInt64: cfg["my_int64"].(int64),
-MyFloat32: cfg["my_float32"].(float32),
-MyFloat64: cfg["my_float64"].(float64),
-MyString: cfg["my_string"].(string),
-MyBool: cfg["my_bool"].(bool),
+obj := &helpergen.SimpleStruct{}
+if v, ok := cfg["my_int"].(int); ok {
+obj.MyInt = v
+}
+if v, ok := cfg["my_int8"].(int); ok {
+obj.MyInt8 = int8(v)
+}
+if v, ok := cfg["my_int16"].(int); ok {
+obj.MyInt16 = int16(v)
+}
+if v, ok := cfg["my_int32"].(int); ok {
+obj.MyInt32 = int32(v)
+}
+if v, ok := cfg["my_int64"].(int); ok {
+obj.MyInt64 = int64(v)
+}
+if v, ok := cfg["my_float32"].(float64); ok {
+obj.MyFloat32 = float32(v)
+}
+if v, ok := cfg["my_float64"].(float64); ok {
+obj.MyFloat64 = v
+}
+if v, ok := cfg["my_string"].(string); ok {
+obj.MyString = v
+}
+if v, ok := cfg["my_bool"].(bool); ok {
+obj.MyBool = v
 }
 return obj
 }`,
@@ -123,22 +169,45 @@ func TestExpanderFromPtrToStruct_ptrsToPrimitives(t *testing.T) {
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) *helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
-return &helpergen.SimpleStruct{}
+return nil
 }
 cfg := l[0].(map[string]interface{})
-obj := &helpergen.SimpleStruct{
-MyInt: ptrToInt(cfg["my_int"].(int)),
-MyInt8: ptrToInt8(cfg["my_int8"].(int8)),
-MyInt16: ptrToInt16(cfg["my_int16"].(int16)),
-MyInt32: ptrToInt32(cfg["my_int32"].(int32)),
-MyInt64: ptrToInt64(cfg["my_int64"].(int64)),
-MyUInt: ptrToUint(cfg["my_u_int"].(uint)),
-MyUInt32: ptrToUint32(cfg["my_u_int32"].(uint32)),
-MyUInt64: ptrToUint64(cfg["my_u_int64"].(uint64)),
-MyFloat32: ptrToFloat32(cfg["my_float32"].(float32)),
-MyFloat64: ptrToFloat64(cfg["my_float64"].(float64)),
-MyString: ptrToString(cfg["my_string"].(string)),
-MyBool: ptrToBool(cfg["my_bool"].(bool)),
+obj := &helpergen.SimpleStruct{}
+if v, ok := cfg["my_int"].(int); ok && v != 0 {
+obj.MyInt = ptrToInt(v)
+}
+if v, ok := cfg["my_int8"].(int); ok && v != 0 {
+obj.MyInt8 = ptrToInt8(int8(v))
+}
+if v, ok := cfg["my_int16"].(int); ok && v != 0 {
+obj.MyInt16 = ptrToInt16(int16(v))
+}
+if v, ok := cfg["my_int32"].(int); ok && v != 0 {
+obj.MyInt32 = ptrToInt32(int32(v))
+}
+if v, ok := cfg["my_int64"].(int); ok && v != 0 {
+obj.MyInt64 = ptrToInt64(int64(v))
+}
+if v, ok := cfg["my_u_int"].(int); ok && v != 0 {
+obj.MyUInt = ptrToUint(uint(v))
+}
+if v, ok := cfg["my_u_int32"].(int); ok && v != 0 {
+obj.MyUInt32 = ptrToUint32(uint32(v))
+}
+if v, ok := cfg["my_u_int64"].(int); ok && v != 0 {
+obj.MyUInt64 = ptrToUint64(uint64(v))
+}
+if v, ok := cfg["my_float32"].(float64); ok && v != 0 {
+obj.MyFloat32 = ptrToFloat32(float32(v))
+}
+if v, ok := cfg["my_float64"].(float64); ok && v != 0 {
+obj.MyFloat64 = ptrToFloat64(v)
+}
+if v, ok := cfg["my_string"].(string); ok && v != "" {
+obj.MyString = ptrToString(v)
+}
+if v, ok := cfg["my_bool"].(bool); ok && v {
+obj.MyBool = ptrToBool(v)
 }
 return obj
 }`,
@@ -166,10 +235,15 @@ if len(l) == 0 || l[0] == nil {
 return helpergen.SimpleStruct{}
 }
 cfg := l[0].(map[string]interface{})
-obj := helpergen.SimpleStruct{
-MyInt: cfg["my_int"].(int),
-MyString: cfg["my_string"].(string),
-MyMap: expandStringMap(cfg["my_map"].(map[string]interface{})),
+obj := helpergen.SimpleStruct{}
+if v, ok := cfg["my_int"].(int); ok {
+obj.MyInt = v
+}
+if v, ok := cfg["my_string"].(string); ok {
+obj.MyString = v
+}
+if v, ok := cfg["my_map"].(map[string]interface{}); ok {
+obj.MyMap = expandStringMap(v)
 }
 return obj
 }`,
@@ -202,17 +276,47 @@ if len(l) == 0 || l[0] == nil {
 return helpergen.SimpleStruct{}
 }
 cfg := l[0].(map[string]interface{})
-obj := helpergen.SimpleStruct{
-SliceOfInt: sliceOfInt(cfg["slice_of_int"].([]interface{})),
-SliceOfInt32: sliceOfInt(cfg["slice_of_int32"].([]interface{})),
-SliceOfInt64: sliceOfInt(cfg["slice_of_int64"].([]interface{})),
-SliceOfString: sliceOfString(cfg["slice_of_string"].([]interface{})),
-SliceOfFloat64: sliceOfFloat(cfg["slice_of_float64"].([]interface{})),
-SliceOfBool: sliceOfBool(cfg["slice_of_bool"].([]interface{})),
-SimpleInt: cfg["simple_int"].(int),
-SimpleString: cfg["simple_string"].(string),
+obj := helpergen.SimpleStruct{}
+if v, ok := listValue(cfg["slice_of_int"]); ok {
+obj.SliceOfInt = sliceOfInt(v)
+}
+if v, ok := listValue(cfg["slice_of_int32"]); ok {
+obj.SliceOfInt32 = expandSliceOfInt32(v)
+}
+if v, ok := listValue(cfg["slice_of_int64"]); ok {
+obj.SliceOfInt64 = expandSliceOfInt64(v)
+}
+if v, ok := listValue(cfg["slice_of_string"]); ok {
+obj.SliceOfString = sliceOfString(v)
+}
+if v, ok := listValue(cfg["slice_of_float64"]); ok {
+obj.SliceOfFloat64 = sliceOfFloat(v)
+}
+if v, ok := listValue(cfg["slice_of_bool"]); ok {
+obj.SliceOfBool = sliceOfBool(v)
+}
+if v, ok := cfg["simple_int"].(int); ok {
+obj.SimpleInt = v
+}
+if v, ok := cfg["simple_string"].(string); ok {
+obj.SimpleString = v
 }
 return obj
+}`,
+		"listValue": "func listValue(v interface{}) ([]interface{}, bool) {\n" + listValueFunc.FuncBody + "\n}",
+		"expandSliceOfInt32": `func expandSliceOfInt32(l []interface{}) []int32 {
+s := make([]int32, len(l))
+for i, v := range l {
+s[i] = int32(v.(int))
+}
+return s
+}`,
+		"expandSliceOfInt64": `func expandSliceOfInt64(l []interface{}) []int64 {
+s := make([]int64, len(l))
+for i, v := range l {
+s[i] = int64(v.(int))
+}
+return s
 }`,
 	}
 	if !reflect.DeepEqual(output, expectedOutput) {
@@ -259,29 +363,47 @@ if len(l) == 0 || l[0] == nil {
 return helpergen.SimpleStruct{}
 }
 cfg := l[0].(map[string]interface{})
-obj := helpergen.SimpleStruct{
-SimpleInt: cfg["simple_int"].(int),
-SimpleString: cfg["simple_string"].(string),
+obj := helpergen.SimpleStruct{}
+if v, ok := cfg["simple_int"].(int); ok {
+obj.SimpleInt = v
 }
-if v, ok := cfg["slice_of_int"].([]interface{}); ok && len(v) > 0 {
+if v, ok := cfg["simple_string"].(string); ok {
+obj.SimpleString = v
+}
+if v, ok := listValue(cfg["slice_of_int"]); ok && len(v) > 0 {
 obj.SliceOfInt = sliceOfInt(v)
 }
-if v, ok := cfg["slice_of_int32"].([]interface{}); ok && len(v) > 0 {
-obj.SliceOfInt32 = sliceOfInt(v)
+if v, ok := listValue(cfg["slice_of_int32"]); ok && len(v) > 0 {
+obj.SliceOfInt32 = expandSliceOfInt32(v)
 }
-if v, ok := cfg["slice_of_int64"].([]interface{}); ok && len(v) > 0 {
-obj.SliceOfInt64 = sliceOfInt(v)
+if v, ok := listValue(cfg["slice_of_int64"]); ok && len(v) > 0 {
+obj.SliceOfInt64 = expandSliceOfInt64(v)
 }
-if v, ok := cfg["slice_of_string"].([]interface{}); ok && len(v) > 0 {
+if v, ok := listValue(cfg["slice_of_string"]); ok && len(v) > 0 {
 obj.SliceOfString = sliceOfString(v)
 }
-if v, ok := cfg["slice_of_float64"].([]interface{}); ok && len(v) > 0 {
+if v, ok := listValue(cfg["slice_of_float64"]); ok && len(v) > 0 {
 obj.SliceOfFloat64 = sliceOfFloat(v)
 }
-if v, ok := cfg["slice_of_bool"].([]interface{}); ok && len(v) > 0 {
+if v, ok := listValue(cfg["slice_of_bool"]); ok && len(v) > 0 {
 obj.SliceOfBool = sliceOfBool(v)
 }
 return obj
+}`,
+		"listValue": "func listValue(v interface{}) ([]interface{}, bool) {\n" + listValueFunc.FuncBody + "\n}",
+		"expandSliceOfInt32": `func expandSliceOfInt32(l []interface{}) []int32 {
+s := make([]int32, len(l))
+for i, v := range l {
+s[i] = int32(v.(int))
+}
+return s
+}`,
+		"expandSliceOfInt64": `func expandSliceOfInt64(l []interface{}) []int64 {
+s := make([]int64, len(l))
+for i, v := range l {
+s[i] = int64(v.(int))
+}
+return s
 }`,
 	}
 	if !reflect.DeepEqual(output, expectedOutput) {
@@ -312,18 +434,34 @@ if len(l) == 0 || l[0] == nil {
 return helpergen.SimpleStruct{}
 }
 cfg := l[0].(map[string]interface{})
-obj := helpergen.SimpleStruct{
-SliceOfInt: sliceOfPtrInt(cfg["slice_of_int"].([]interface{})),
-SliceOfInt32: sliceOfPtrInt(cfg["slice_of_int32"].([]interface{})),
-SliceOfInt64: sliceOfPtrInt(cfg["slice_of_int64"].([]interface{})),
-SliceOfString: sliceOfPtrString(cfg["slice_of_string"].([]interface{})),
-SliceOfFloat64: sliceOfPtrFloat(cfg["slice_of_float64"].([]interface{})),
-SliceOfBool: sliceOfPtrBool(cfg["slice_of_bool"].([]interface{})),
-SimpleInt: cfg["simple_int"].(int),
-SimpleString: cfg["simple_string"].(string),
+obj := helpergen.SimpleStruct{}
+if v, ok := listValue(cfg["slice_of_int"]); ok {
+obj.SliceOfInt = sliceOfPtrInt(v)
+}
+if v, ok := listValue(cfg["slice_of_int32"]); ok {
+obj.SliceOfInt32 = sliceOfPtrInt(v)
+}
+if v, ok := listValue(cfg["slice_of_int64"]); ok {
+obj.SliceOfInt64 = sliceOfPtrInt(v)
+}
+if v, ok := listValue(cfg["slice_of_string"]); ok {
+obj.SliceOfString = sliceOfPtrString(v)
+}
+if v, ok := listValue(cfg["slice_of_float64"]); ok {
+obj.SliceOfFloat64 = sliceOfPtrFloat(v)
+}
+if v, ok := listValue(cfg["slice_of_bool"]); ok {
+obj.SliceOfBool = sliceOfPtrBool(v)
+}
+if v, ok := cfg["simple_int"].(int); ok {
+obj.SimpleInt = v
+}
+if v, ok := cfg["simple_string"].(string); ok {
+obj.SimpleString = v
 }
 return obj
 }`,
+		"listValue": "func listValue(v interface{}) ([]interface{}, bool) {\n" + listValueFunc.FuncBody + "\n}",
 	}
 	if !reflect.DeepEqual(output, expectedOutput) {
 		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
@@ -352,10 +490,15 @@ if len(l) == 0 || l[0] == nil {
 return helpergen.SimpleStruct{}
 }
 cfg := l[0].(map[string]interface{})
-obj := helpergen.SimpleStruct{
-NestedSlice: expandNestedStruct(cfg["nested_slice"].([]interface{})),
-SimpleInt: cfg["simple_int"].(int),
-SimpleString: cfg["simple_string"].(string),
+obj := helpergen.SimpleStruct{}
+if v, ok := listValue(cfg["nested_slice"]); ok {
+obj.NestedSlice = expandNestedStruct(v)
+}
+if v, ok := cfg["simple_int"].(int); ok {
+obj.SimpleInt = v
+}
+if v, ok := cfg["simple_string"].(string); ok {
+obj.SimpleString = v
 }
 return obj
 }`,
@@ -366,13 +509,17 @@ return []helpergen.NestedStruct{}
 obj := make([]helpergen.NestedStruct, len(l), len(l))
 for i, n := range l {
 cfg := n.(map[string]interface{})
-obj[i] = helpergen.NestedStruct{
-NestedInt: cfg["nested_int"].(int),
-NestedString: cfg["nested_string"].(string),
+obj[i] = helpergen.NestedStruct{}
+if v, ok := cfg["nested_int"].(int); ok {
+obj[i].NestedInt = v
+}
+if v, ok := cfg["nested_string"].(string); ok {
+obj[i].NestedString = v
 }
 }
 return obj
 }`,
+		"listValue": "func listValue(v interface{}) ([]interface{}, bool) {\n" + listValueFunc.FuncBody + "\n}",
 	}
 	if !reflect.DeepEqual(output, expectedOutput) {
 		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
@@ -401,10 +548,15 @@ if len(l) == 0 || l[0] == nil {
 return helpergen.SimpleStruct{}
 }
 cfg := l[0].(map[string]interface{})
-obj := helpergen.SimpleStruct{
-NestedSlice: expandNestedStruct(cfg["nested_slice"].([]interface{})),
-SimpleInt: cfg["simple_int"].(int),
-SimpleString: cfg["simple_string"].(string),
+obj := helpergen.SimpleStruct{}
+if v, ok := listValue(cfg["nested_slice"]); ok {
+obj.NestedSlice = expandNestedStruct(v)
+}
+if v, ok := cfg["simple_int"].(int); ok {
+obj.SimpleInt = v
+}
+if v, ok := cfg["simple_string"].(string); ok {
+obj.SimpleString = v
 }
 return obj
 }`,
@@ -415,13 +567,17 @@ return []*helpergen.NestedStruct{}
 obj := make([]*helpergen.NestedStruct, len(l), len(l))
 for i, n := range l {
 cfg := n.(map[string]interface{})
-obj[i] = &helpergen.NestedStruct{
-NestedInt: cfg["nested_int"].(int),
-NestedString: cfg["nested_string"].(string),
+obj[i] = &helpergen.NestedStruct{}
+if v, ok := cfg["nested_int"].(int); ok {
+obj[i].NestedInt = v
+}
+if v, ok := cfg["nested_string"].(string); ok {
+obj[i].NestedString = v
 }
 }
 return obj
 }`,
+		"listValue": "func listValue(v interface{}) ([]interface{}, bool) {\n" + listValueFunc.FuncBody + "\n}",
 	}
 	if !reflect.DeepEqual(output, expectedOutput) {
 		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
@@ -451,11 +607,18 @@ if len(l) == 0 || l[0] == nil {
 return helpergen.SimpleStruct{}
 }
 cfg := l[0].(map[string]interface{})
-obj := helpergen.SimpleStruct{
-MyInt: cfg["my_int"].(int),
-MyString: cfg["my_string"].(string),
-MyBool: cfg["my_bool"].(bool),
-MyNested: expandNestedStruct(cfg["my_nested"].([]interface{})),
+obj := helpergen.SimpleStruct{}
+if v, ok := cfg["my_int"].(int); ok {
+obj.MyInt = v
+}
+if v, ok := cfg["my_string"].(string); ok {
+obj.MyString = v
+}
+if v, ok := cfg["my_bool"].(bool); ok {
+obj.MyBool = v
+}
+if v, ok := cfg["my_nested"].([]interface{}); ok {
+obj.MyNested = expandNestedStruct(v)
 }
 return obj
 }`,
@@ -464,9 +627,12 @@ if len(l) == 0 || l[0] == nil {
 return helpergen.NestedStruct{}
 }
 cfg := l[0].(map[string]interface{})
-obj := helpergen.NestedStruct{
-NestedInt: cfg["nested_int"].(int),
-NestedString: cfg["nested_string"].(string),
+obj := helpergen.NestedStruct{}
+if v, ok := cfg["nested_int"].(int); ok {
+obj.NestedInt = v
+}
+if v, ok := cfg["nested_string"].(string); ok {
+obj.NestedString = v
 }
 return obj
 }`,
@@ -511,15 +677,531 @@ if len(l) == 0 || l[0] == nil {
 return helpergen.SimpleStruct{}
 }
 cfg := l[0].(map[string]interface{})
+obj := helpergen.SimpleStruct{}
+if v, ok := cfg["my_string"].(string); ok {
+obj.MyString = v
+}
+if v, ok := cfg["my_bool"].(bool); ok {
+obj.MyBool = v
+}
+if v, ok := cfg["my_int"].(int); ok && v != 0 {
+obj.MyInt = v
+}
+if v, ok := cfg["my_float"].(float64); ok && v != 0 {
+obj.MyFloat = v
+}
+return obj
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestExpanderFromStruct_optionalPtrs(t *testing.T) {
+	type NestedStruct struct {
+		NestedInt int
+	}
+	type SimpleStruct struct {
+		MyInt      *int              `api:"optional"`
+		MyString   *string           `api:"optional"`
+		MyFloat    *float64          `api:"optional"`
+		MyBool     *bool             `api:"optional"`
+		MySlice    []string          `api:"optional"`
+		MyMap      map[string]string `api:"optional"`
+		MyNested   *NestedStruct     `api:"optional"`
+		MyDuration *time.Duration    `api:"optional"`
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+	}
+	hg.InlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") != "optional"
+	}
+	hg.OutlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") == "optional"
+	}
+
+	output := hg.ExpandersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.SimpleStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.SimpleStruct{}
+if v, ok := cfg["my_int"].(int); ok && v != 0 {
+obj.MyInt = ptrToInt(v)
+}
+if v, ok := cfg["my_string"].(string); ok && v != "" {
+obj.MyString = ptrToString(v)
+}
+if v, ok := cfg["my_float"].(float64); ok && v != 0 {
+obj.MyFloat = ptrToFloat64(v)
+}
+if v, ok := cfg["my_bool"].(bool); ok && v {
+obj.MyBool = ptrToBool(v)
+}
+if v, ok := listValue(cfg["my_slice"]); ok && len(v) > 0 {
+obj.MySlice = sliceOfString(v)
+}
+if v, ok := cfg["my_map"].(map[string]interface{}); ok && len(v) > 0 {
+obj.MyMap = expandStringMap(v)
+}
+if v, ok := cfg["my_nested"].([]interface{}); ok && len(v) > 0 {
+obj.MyNested = expandNestedStruct(v)
+}
+if v, ok := cfg["my_duration"].(string); ok && v != "" {
+obj.MyDuration = expandDurationPtr(v)
+}
+return obj
+}`,
+		"expandNestedStruct": `func expandNestedStruct(l []interface{}) *helpergen.NestedStruct {
+if len(l) == 0 || l[0] == nil {
+return nil
+}
+cfg := l[0].(map[string]interface{})
+obj := &helpergen.NestedStruct{}
+if v, ok := cfg["nested_int"].(int); ok {
+obj.NestedInt = v
+}
+return obj
+}`,
+		"expandDurationPtr": `func expandDurationPtr(s string) *time.Duration {
+if s == "" {
+return nil
+}
+d, _ := time.ParseDuration(s)
+return &d
+}`,
+		"listValue": "func listValue(v interface{}) ([]interface{}, bool) {\n" + listValueFunc.FuncBody + "\n}",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestExpanderFromStruct_embeddedStruct(t *testing.T) {
+	type EmbeddedStruct struct {
+		EmbeddedInt    int
+		EmbeddedString string `api:"optional"`
+	}
+	type SimpleStruct struct {
+		*EmbeddedStruct
+		MyInt    int
+		MyString string `api:"optional"`
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+	}
+	hg.InlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") != "optional"
+	}
+	hg.OutlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") == "optional"
+	}
+
+	output := hg.ExpandersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.SimpleStruct{}
+}
+cfg := l[0].(map[string]interface{})
 obj := helpergen.SimpleStruct{
-MyString: cfg["my_string"].(string),
-MyBool: cfg["my_bool"].(bool),
+EmbeddedStruct: &helpergen.EmbeddedStruct{},
+}
+if v, ok := cfg["embedded_int"].(int); ok {
+obj.EmbeddedStruct.EmbeddedInt = v
 }
 if v, ok := cfg["my_int"].(int); ok {
 obj.MyInt = v
 }
-if v, ok := cfg["my_float"].(float64); ok {
-obj.MyFloat = v
+if v, ok := cfg["embedded_string"].(string); ok && v != "" {
+obj.EmbeddedStruct.EmbeddedString = v
+}
+if v, ok := cfg["my_string"].(string); ok && v != "" {
+obj.MyString = v
+}
+return obj
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestExpanderFromStruct_nestedEmbeddedStruct(t *testing.T) {
+	type EmbeddedStruct struct {
+		EmbeddedInt int
+	}
+	type SimpleStruct struct {
+		EmbeddedStruct
+		MyInt int
+	}
+	hg := &HelperGenerator{
+		InputVarName:        "cfg",
+		OutputVarName:       "obj",
+		NestEmbeddedStructs: true,
+	}
+
+	output := hg.ExpandersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.SimpleStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.SimpleStruct{}
+if v, ok := cfg["embedded_struct"].([]interface{}); ok {
+obj.EmbeddedStruct = expandEmbeddedStruct(v)
+}
+if v, ok := cfg["my_int"].(int); ok {
+obj.MyInt = v
+}
+return obj
+}`,
+		"expandEmbeddedStruct": `func expandEmbeddedStruct(l []interface{}) helpergen.EmbeddedStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.EmbeddedStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.EmbeddedStruct{}
+if v, ok := cfg["embedded_int"].(int); ok {
+obj.EmbeddedInt = v
+}
+return obj
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+type recursiveStruct struct {
+	MyInt int
+	Next  *recursiveStruct
+}
+
+func TestExpanderFromStruct_shadowedField(t *testing.T) {
+	type FirstStruct struct {
+		Name   string
+		Labels string
+	}
+	type SecondStruct struct {
+		Labels bool
+	}
+	type SimpleStruct struct {
+		FirstStruct
+		SecondStruct
+		Name bool
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+	}
+
+	output := hg.ExpandersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.SimpleStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.SimpleStruct{}
+if v, ok := cfg["labels"].(bool); ok {
+obj.SecondStruct.Labels = v
+}
+if v, ok := cfg["name"].(bool); ok {
+obj.Name = v
+}
+return obj
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestExpanderFromStruct_cycle(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+	}
+
+	output := hg.ExpandersFromStruct(recursiveStruct{})
+	expectedOutput := map[string]string{
+		"expandrecursiveStruct": `func expandrecursiveStruct(l []interface{}) helpergen.recursiveStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.recursiveStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.recursiveStruct{}
+if v, ok := cfg["my_int"].(int); ok {
+obj.MyInt = v
+}
+// TODO: Next: Cycle detected: helpergen.recursiveStruct -> helpergen.recursiveStruct (see MaxDepth)
+return obj
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestExpanderFromStruct_maxDepth(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+		MaxDepth:      1,
+	}
+
+	output := hg.ExpandersFromStruct(recursiveStruct{})
+	expectedOutput := map[string]string{
+		"expandrecursiveStruct": `func expandrecursiveStruct(l []interface{}) helpergen.recursiveStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.recursiveStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.recursiveStruct{}
+if v, ok := cfg["my_int"].(int); ok {
+obj.MyInt = v
+}
+if v, ok := cfg["next"].([]interface{}); ok && len(v) > 0 {
+obj.Next = expandrecursiveStructLevel2(v)
+}
+return obj
+}`,
+		"expandrecursiveStructLevel2": `func expandrecursiveStructLevel2(l []interface{}) *helpergen.recursiveStruct {
+if len(l) == 0 || l[0] == nil {
+return nil
+}
+cfg := l[0].(map[string]interface{})
+obj := &helpergen.recursiveStruct{}
+if v, ok := cfg["my_int"].(int); ok {
+obj.MyInt = v
+}
+if v, ok := cfg["next"].(string); ok && v != "" {
+obj.Next = expandrecursiveStructJSON(v)
+}
+return obj
+}`,
+		"expandrecursiveStructJSON": `func expandrecursiveStructJSON(s string) *helpergen.recursiveStruct {
+var obj *helpergen.recursiveStruct
+if s == "" {
+return obj
+}
+json.Unmarshal([]byte(s), &obj)
+return obj
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestExpanderFromStruct_freeFormJSON(t *testing.T) {
+	type SimpleStruct struct {
+		MyInterface interface{}
+		MyMap       map[string]interface{} `api:"optional"`
+		MyList      []interface{}
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+	}
+	hg.InlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") != "optional"
+	}
+	hg.OutlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") == "optional"
+	}
+
+	output := hg.ExpandersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.SimpleStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.SimpleStruct{}
+if v, ok := cfg["my_interface"].(string); ok {
+obj.MyInterface = expandInterfaceJSON(v)
+}
+if v, ok := cfg["my_list"].(string); ok {
+obj.MyList = expandInterfaceListJSON(v)
+}
+if v, ok := cfg["my_map"].(string); ok && v != "" {
+obj.MyMap = expandInterfaceMapJSON(v)
+}
+return obj
+}`,
+		"expandInterfaceJSON": `func expandInterfaceJSON(s string) interface {} {
+var obj interface {}
+if s == "" {
+return obj
+}
+json.Unmarshal([]byte(s), &obj)
+return obj
+}`,
+		"expandInterfaceListJSON": `func expandInterfaceListJSON(s string) []interface {} {
+var obj []interface {}
+if s == "" {
+return obj
+}
+json.Unmarshal([]byte(s), &obj)
+return obj
+}`,
+		"expandInterfaceMapJSON": `func expandInterfaceMapJSON(s string) map[string]interface {} {
+var obj map[string]interface {}
+if s == "" {
+return obj
+}
+json.Unmarshal([]byte(s), &obj)
+return obj
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestExpanderFromStruct_builtinTypes(t *testing.T) {
+	type SimpleStruct struct {
+		MyBytes    []byte
+		MyDuration time.Duration
+		MyTime     *time.Time `api:"optional"`
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+	}
+	hg.InlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") != "optional"
+	}
+	hg.OutlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") == "optional"
+	}
+
+	output := hg.ExpandersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.SimpleStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.SimpleStruct{}
+if v, ok := cfg["my_bytes"].(string); ok {
+obj.MyBytes = expandBase64(v)
+}
+if v, ok := cfg["my_duration"].(string); ok {
+obj.MyDuration = expandDuration(v)
+}
+if v, ok := cfg["my_time"].(string); ok && v != "" {
+obj.MyTime = expandTimePtr(v)
+}
+return obj
+}`,
+		"expandBase64": `func expandBase64(s string) []uint8 {
+b, _ := base64.StdEncoding.DecodeString(s)
+return b
+}`,
+		"expandDuration": `func expandDuration(s string) time.Duration {
+d, _ := time.ParseDuration(s)
+return d
+}`,
+		"expandTimePtr": `func expandTimePtr(s string) *time.Time {
+if s == "" {
+return nil
+}
+t, _ := time.Parse(time.RFC3339, s)
+return &t
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+type unevenStruct struct {
+	Direct  *recursiveStruct
+	Wrapped *wrappedStruct
+}
+
+type wrappedStruct struct {
+	Next *recursiveStruct
+}
+
+func TestExpanderFromStruct_maxDepthLevels(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+		MaxDepth:      2,
+	}
+
+	output := hg.ExpandersFromStruct(unevenStruct{})
+	// recursiveStruct reached via Wrapped is at the same level as Direct.Next
+	expectedOutput := map[string]string{
+		"expandrecursiveStructJSON": `func expandrecursiveStructJSON(s string) *helpergen.recursiveStruct {
+var obj *helpergen.recursiveStruct
+if s == "" {
+return obj
+}
+json.Unmarshal([]byte(s), &obj)
+return obj
+}`,
+		"expandrecursiveStructLevel2": `func expandrecursiveStructLevel2(l []interface{}) *helpergen.recursiveStruct {
+if len(l) == 0 || l[0] == nil {
+return nil
+}
+cfg := l[0].(map[string]interface{})
+obj := &helpergen.recursiveStruct{}
+if v, ok := cfg["my_int"].(int); ok {
+obj.MyInt = v
+}
+if v, ok := cfg["next"].([]interface{}); ok && len(v) > 0 {
+obj.Next = expandrecursiveStructLevel3(v)
+}
+return obj
+}`,
+		"expandrecursiveStructLevel3": `func expandrecursiveStructLevel3(l []interface{}) *helpergen.recursiveStruct {
+if len(l) == 0 || l[0] == nil {
+return nil
+}
+cfg := l[0].(map[string]interface{})
+obj := &helpergen.recursiveStruct{}
+if v, ok := cfg["my_int"].(int); ok {
+obj.MyInt = v
+}
+if v, ok := cfg["next"].(string); ok && v != "" {
+obj.Next = expandrecursiveStructJSON(v)
+}
+return obj
+}`,
+		"expandunevenStruct": `func expandunevenStruct(l []interface{}) helpergen.unevenStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.unevenStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.unevenStruct{}
+if v, ok := cfg["direct"].([]interface{}); ok && len(v) > 0 {
+obj.Direct = expandrecursiveStructLevel2(v)
+}
+if v, ok := cfg["wrapped"].([]interface{}); ok && len(v) > 0 {
+obj.Wrapped = expandwrappedStructLevel2(v)
+}
+return obj
+}`,
+		"expandwrappedStructLevel2": `func expandwrappedStructLevel2(l []interface{}) *helpergen.wrappedStruct {
+if len(l) == 0 || l[0] == nil {
+return nil
+}
+cfg := l[0].(map[string]interface{})
+obj := &helpergen.wrappedStruct{}
+if v, ok := cfg["next"].([]interface{}); ok && len(v) > 0 {
+obj.Next = expandrecursiveStructLevel3(v)
 }
 return obj
 }`,
@@ -20,30 +20,26 @@ func (hg *HelperGenerator) generateFlattenersFromStruct(iface interface{}) strin
 	t := reflect.TypeOf(iface)
 	rawType := getRawType(t)
 
-	funcName := flattenerFuncNameFromType(t)
+	hg.pushType(t)
+	defer hg.popType()
+
+	// Every function starts with its own variable names
+	mapVarName, mapValueName := hg.mapVarName, hg.mapValueName
+	hg.mapVarName, hg.mapValueName = hg.OutputVarName, hg.InputVarName
+	defer func() {
+		hg.mapVarName, hg.mapValueName = mapVarName, mapValueName
+	}()
+
+	funcName := hg.levelFuncName(flattenerFuncNameFromType(t))
 	funcBody := hg.flattenerDeclarationBeginning(t)
 
+	fields := hg.structFields(rawType, iface)
+
 	// Inline fields (typically those we never expect to be empty)
-	for i := 0; i < rawType.NumField(); i++ {
-		sf := rawType.Field(i)
-		body, err := hg.inlineFlattenerField(sf.Name, sf.Type, iface, &sf, false)
-		if err != nil {
-			log.Printf("Skipping %s (inline): %s", sf.Name, err)
-			continue
-		}
-		funcBody += body
-	}
+	funcBody += hg.flattenerFields(fields, hg.inlineFlattenerField, "inline")
 
 	// Outline fields (typically optional)
-	for i := 0; i < rawType.NumField(); i++ {
-		sf := rawType.Field(i)
-		body, err := hg.outlineFlattenerField(sf.Name, sf.Type, iface, &sf, false)
-		if err != nil {
-			log.Printf("Skipping %s (outline): %s", sf.Name, err)
-			continue
-		}
-		funcBody += body
-	}
+	funcBody += hg.flattenerFields(fields, hg.outlineFlattenerField, "outline")
 
 	funcBody += hg.flattenerDeclarationEnd(t)
 
@@ -58,6 +54,47 @@ func (hg *HelperGenerator) generateFlattenersFromStruct(iface interface{}) strin
 	return funcName
 }
 
+type flattenerFieldFunc func(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField, isNested bool) (string, error)
+
+func (hg *HelperGenerator) flattenerFields(fields []*structField, fieldFunc flattenerFieldFunc, mode string) string {
+	body := ""
+	for _, f := range fields {
+		if f.isPromoted {
+			body += hg.embeddedFlattenerFields(f, fieldFunc, mode)
+			continue
+		}
+		fieldBody, err := fieldFunc(f.Name, f.Type, f.iface, &f.StructField, false)
+		if err != nil {
+			log.Printf("Skipping %s (%s): %s", f.Name, mode, err)
+			body += skippedFieldCode(f.Name, err)
+			continue
+		}
+		body += fieldBody
+	}
+	return body
+}
+
+// Promoted fields are read from the embedded struct
+// and written into the same map as the parent's fields
+func (hg *HelperGenerator) embeddedFlattenerFields(sf *structField, fieldFunc flattenerFieldFunc, mode string) string {
+	mapValueName := hg.mapValueName
+	if mapValueName == "" {
+		mapValueName = hg.InputVarName
+	}
+	embeddedValueName := mapValueName + "." + sf.Name
+	hg.mapValueName = embeddedValueName
+	defer func() {
+		hg.mapValueName = mapValueName
+	}()
+
+	body := hg.flattenerFields(sf.promoted, fieldFunc, mode)
+
+	if sf.Type.Kind() == reflect.Ptr && body != "" {
+		return fmt.Sprintf("if %s != nil {\n%s}\n", embeddedValueName, body)
+	}
+	return body
+}
+
 func (hg *HelperGenerator) flattenerDeclarationBeginning(t reflect.Type) string {
 	if t.Kind() == reflect.Slice {
 		body := hg.mapVarName + ` := make([]interface{}, len(in), len(in))
@@ -156,6 +193,15 @@ func (hg *HelperGenerator) flattenerFieldValue(kind reflect.Kind, sf *reflect.St
 		inputVarName = hg.mapValueName
 	}
 
+	conv, err := hg.fieldConversion(kind, sfType)
+	if err != nil {
+		return "", err
+	}
+	if conv != u.NoConversion {
+		funcName := hg.conversionFlattenerForType(conv, sfType)
+		return fmt.Sprintf("%s(%s.%s)", funcName, inputVarName, sf.Name), nil
+	}
+
 	switch kind {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
@@ -168,19 +214,19 @@ func (hg *HelperGenerator) flattenerFieldValue(kind reflect.Kind, sf *reflect.St
 		return fmt.Sprintf("%s%s.%s", sfPtr, inputVarName, sf.Name), nil
 	case reflect.Map:
 		// TODO: map[string]*string
-		// TODO: map[string]*string
-		// TODO: map[string]int
-		// TODO: map[string]bool
-		// TODO: map[string]float
-		return fmt.Sprintf("%s.%s", inputVarName, sf.Name), nil
+		if sfType.Elem().Kind() == reflect.Ptr {
+			return fmt.Sprintf("%s.%s", inputVarName, sf.Name), nil
+		}
+		funcName := hg.interfaceFlattenerForType(sfType)
+		return fmt.Sprintf("%s(%s.%s)", funcName, inputVarName, sf.Name), nil
 	case reflect.Slice:
-		// TODO: s.Type == TypeSet
 		sliceOf := sfType.Elem()
 		switch sliceOf.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 			reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
 			// Slice of primitive data types
-			return fmt.Sprintf("%s.%s", inputVarName, sf.Name), nil
+			funcName := hg.interfaceFlattenerForType(sfType)
+			return fmt.Sprintf("%s(%s.%s)", funcName, inputVarName, sf.Name), nil
 		case reflect.Ptr:
 			ptrTo := sliceOf.Elem()
 			funcName := hg.primitivePtrSliceFlattenerForType(ptrTo, sfType)
@@ -200,6 +246,47 @@ func (hg *HelperGenerator) flattenerFieldValue(kind reflect.Kind, sf *reflect.St
 	return "", fmt.Errorf("Unable to process: %s", f)
 }
 
+// The SDK only accepts []interface{} & map[string]interface{}
+// with elements of the types it stores (e.g. int instead of int32)
+func (hg *HelperGenerator) interfaceFlattenerForType(t reflect.Type) string {
+	elemName := t.Elem().Name()
+	elemName = strings.ToUpper(elemName[:1]) + elemName[1:]
+	elemValue := "v"
+	if schemaType := schemaGoType(t.Elem().Kind()); schemaType != t.Elem().String() {
+		elemValue = schemaType + "(v)"
+	}
+
+	if t.Kind() == reflect.Map {
+		funcName := "flattenMapOf" + elemName
+		hg.declarations[funcName] = &FunctionDeclaration{
+			PkgPath:   t.PkgPath(),
+			FuncName:  funcName,
+			Arguments: "m " + t.String(),
+			Outputs:   "map[string]interface{}",
+			FuncBody: `att := make(map[string]interface{}, len(m))
+for k, v := range m {
+att[k] = ` + elemValue + `
+}
+return att`,
+		}
+		return funcName
+	}
+
+	funcName := "flattenSliceOf" + elemName
+	hg.declarations[funcName] = &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "l " + t.String(),
+		Outputs:   "[]interface{}",
+		FuncBody: `att := make([]interface{}, len(l))
+for i, v := range l {
+att[i] = ` + elemValue + `
+}
+return att`,
+	}
+	return funcName
+}
+
 func (hg *HelperGenerator) primitivePtrSliceFlattenerForType(t reflect.Type, sfType reflect.Type) string {
 	switch t.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -217,6 +304,10 @@ func (hg *HelperGenerator) primitivePtrSliceFlattenerForType(t reflect.Type, sfT
 	return ""
 }
 
+func FlattenerFuncName(iface interface{}) string {
+	return flattenerFuncNameFromType(reflect.TypeOf(iface))
+}
+
 func flattenerFuncNameFromType(t reflect.Type) string {
 	// pkg.TypeName
 	parts := strings.Split(t.String(), ".")
@@ -3,6 +3,7 @@ package helpergen
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 )
@@ -240,6 +241,7 @@ return []interface{}{att}
 func TestFlattenersFromStruct_primitiveSlice(t *testing.T) {
 	type SimpleStruct struct {
 		SliceOfInt     []int
+		SliceOfInt32   []int32
 		SliceOfString  []string
 		SliceOfBool    []bool
 		SliceOfFloat64 []float64
@@ -253,11 +255,76 @@ func TestFlattenersFromStruct_primitiveSlice(t *testing.T) {
 	expectedOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
 att := make(map[string]interface{})
-att["slice_of_int"] = in.SliceOfInt
-att["slice_of_string"] = in.SliceOfString
-att["slice_of_bool"] = in.SliceOfBool
-att["slice_of_float64"] = in.SliceOfFloat64
+att["slice_of_int"] = flattenSliceOfInt(in.SliceOfInt)
+att["slice_of_int32"] = flattenSliceOfInt32(in.SliceOfInt32)
+att["slice_of_string"] = flattenSliceOfString(in.SliceOfString)
+att["slice_of_bool"] = flattenSliceOfBool(in.SliceOfBool)
+att["slice_of_float64"] = flattenSliceOfFloat64(in.SliceOfFloat64)
 return []interface{}{att}
+}`,
+		"flattenSliceOfInt": `func flattenSliceOfInt(l []int) []interface{} {
+att := make([]interface{}, len(l))
+for i, v := range l {
+att[i] = v
+}
+return att
+}`,
+		"flattenSliceOfInt32": `func flattenSliceOfInt32(l []int32) []interface{} {
+att := make([]interface{}, len(l))
+for i, v := range l {
+att[i] = int(v)
+}
+return att
+}`,
+		"flattenSliceOfString": `func flattenSliceOfString(l []string) []interface{} {
+att := make([]interface{}, len(l))
+for i, v := range l {
+att[i] = v
+}
+return att
+}`,
+		"flattenSliceOfBool": `func flattenSliceOfBool(l []bool) []interface{} {
+att := make([]interface{}, len(l))
+for i, v := range l {
+att[i] = v
+}
+return att
+}`,
+		"flattenSliceOfFloat64": `func flattenSliceOfFloat64(l []float64) []interface{} {
+att := make([]interface{}, len(l))
+for i, v := range l {
+att[i] = v
+}
+return att
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestFlattenersFromStruct_stringMap(t *testing.T) {
+	type SimpleStruct struct {
+		MyMap map[string]string
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+
+	output := hg.FlattenersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
+att := make(map[string]interface{})
+att["my_map"] = flattenMapOfString(in.MyMap)
+return []interface{}{att}
+}`,
+		"flattenMapOfString": `func flattenMapOfString(m map[string]string) map[string]interface{} {
+att := make(map[string]interface{}, len(m))
+for k, v := range m {
+att[k] = v
+}
+return att
 }`,
 	}
 	if !reflect.DeepEqual(output, expectedOutput) {
@@ -436,3 +503,271 @@ return []interface{}{att}
 		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
 	}
 }
+
+func TestFlattenersFromStruct_embeddedStruct(t *testing.T) {
+	type EmbeddedStruct struct {
+		EmbeddedInt    int
+		EmbeddedString string `api:"optional"`
+	}
+	type SimpleStruct struct {
+		*EmbeddedStruct
+		MyInt    int
+		MyString string `api:"optional"`
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	hg.InlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") != "optional"
+	}
+	hg.OutlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		if sf.Tag.Get("api") == "optional" {
+			s.Optional = true
+			return k, true
+		}
+		return k, false
+	}
+
+	output := hg.FlattenersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
+att := make(map[string]interface{})
+if in.EmbeddedStruct != nil {
+att["embedded_int"] = in.EmbeddedStruct.EmbeddedInt
+}
+att["my_int"] = in.MyInt
+if in.EmbeddedStruct != nil {
+if in.EmbeddedStruct.EmbeddedString != "" {
+att["embedded_string"] = in.EmbeddedStruct.EmbeddedString
+}
+}
+if in.MyString != "" {
+att["my_string"] = in.MyString
+}
+return []interface{}{att}
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestFlattenersFromStruct_nestedEmbeddedStruct(t *testing.T) {
+	type EmbeddedStruct struct {
+		EmbeddedInt int
+	}
+	type SimpleStruct struct {
+		EmbeddedStruct
+		MyInt int
+	}
+	hg := &HelperGenerator{
+		InputVarName:        "in",
+		OutputVarName:       "att",
+		NestEmbeddedStructs: true,
+	}
+
+	output := hg.FlattenersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
+att := make(map[string]interface{})
+att["embedded_struct"] = flattenEmbeddedStruct(in.EmbeddedStruct)
+att["my_int"] = in.MyInt
+return []interface{}{att}
+}`,
+		"flattenEmbeddedStruct": `func flattenEmbeddedStruct(in helpergen.EmbeddedStruct) []interface{} {
+att := make(map[string]interface{})
+att["embedded_int"] = in.EmbeddedInt
+return []interface{}{att}
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestFlattenersFromStruct_shadowedField(t *testing.T) {
+	type FirstStruct struct {
+		Name   string
+		Labels string
+	}
+	type SecondStruct struct {
+		Labels bool
+	}
+	type SimpleStruct struct {
+		FirstStruct
+		SecondStruct
+		Name bool
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+
+	output := hg.FlattenersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
+att := make(map[string]interface{})
+att["labels"] = in.SecondStruct.Labels
+att["name"] = in.Name
+return []interface{}{att}
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestFlattenersFromStruct_cycle(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+
+	output := hg.FlattenersFromStruct(recursiveStruct{})
+	expectedOutput := map[string]string{
+		"flattenrecursiveStruct": `func flattenrecursiveStruct(in helpergen.recursiveStruct) []interface{} {
+att := make(map[string]interface{})
+att["my_int"] = in.MyInt
+// TODO: Next: Cycle detected: helpergen.recursiveStruct -> helpergen.recursiveStruct (see MaxDepth)
+return []interface{}{att}
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestFlattenersFromStruct_maxDepth(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+		MaxDepth:      1,
+	}
+
+	output := hg.FlattenersFromStruct(recursiveStruct{})
+	expectedOutput := map[string]string{
+		"flattenrecursiveStruct": `func flattenrecursiveStruct(in helpergen.recursiveStruct) []interface{} {
+att := make(map[string]interface{})
+att["my_int"] = in.MyInt
+att["next"] = flattenrecursiveStructLevel2(in.Next)
+return []interface{}{att}
+}`,
+		"flattenrecursiveStructLevel2": `func flattenrecursiveStructLevel2(in *helpergen.recursiveStruct) []interface{} {
+att := make(map[string]interface{})
+att["my_int"] = in.MyInt
+att["next"] = flattenrecursiveStructJSON(in.Next)
+return []interface{}{att}
+}`,
+		"flattenrecursiveStructJSON": `func flattenrecursiveStructJSON(in *helpergen.recursiveStruct) string {
+b, err := json.Marshal(in)
+if err != nil || string(b) == "null" {
+return ""
+}
+return string(b)
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestFlattenersFromStruct_freeFormJSON(t *testing.T) {
+	type SimpleStruct struct {
+		MyInterface interface{}
+		MyMap       map[string]interface{} `api:"optional"`
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	hg.InlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") != "optional"
+	}
+	hg.OutlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		if sf.Tag.Get("api") == "optional" {
+			s.Optional = true
+			return k, true
+		}
+		return k, false
+	}
+
+	output := hg.FlattenersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
+att := make(map[string]interface{})
+att["my_interface"] = flattenInterfaceJSON(in.MyInterface)
+if len(in.MyMap) > 0 {
+att["my_map"] = flattenInterfaceMapJSON(in.MyMap)
+}
+return []interface{}{att}
+}`,
+		"flattenInterfaceJSON": `func flattenInterfaceJSON(in interface {}) string {
+b, err := json.Marshal(in)
+if err != nil || string(b) == "null" {
+return ""
+}
+return string(b)
+}`,
+		"flattenInterfaceMapJSON": `func flattenInterfaceMapJSON(in map[string]interface {}) string {
+b, err := json.Marshal(in)
+if err != nil || string(b) == "null" {
+return ""
+}
+return string(b)
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestFlattenersFromStruct_builtinTypes(t *testing.T) {
+	type SimpleStruct struct {
+		MyBytes    []byte
+		MyDuration time.Duration
+		MyTime     time.Time `api:"optional"`
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	hg.InlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") != "optional"
+	}
+	hg.OutlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		if sf.Tag.Get("api") == "optional" {
+			s.Optional = true
+			return k, true
+		}
+		return k, false
+	}
+
+	output := hg.FlattenersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
+att := make(map[string]interface{})
+att["my_bytes"] = flattenBase64(in.MyBytes)
+att["my_duration"] = flattenDuration(in.MyDuration)
+if !in.MyTime.IsZero() {
+att["my_time"] = flattenTime(in.MyTime)
+}
+return []interface{}{att}
+}`,
+		"flattenBase64": `func flattenBase64(in []uint8) string {
+return base64.StdEncoding.EncodeToString(in)
+}`,
+		"flattenDuration": `func flattenDuration(in time.Duration) string {
+return in.String()
+}`,
+		"flattenTime": `func flattenTime(in time.Time) string {
+if in.IsZero() {
+return ""
+}
+return in.Format(time.RFC3339)
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
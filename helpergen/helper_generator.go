@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"strings"
 	"text/template"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
 )
 
 type FunctionDeclaration struct {
@@ -26,13 +28,25 @@ type HelperGenerator struct {
 	InputVarName           string
 	OutputVarName          string
 
+	// Embedded (anonymous) struct fields are promoted to the parent
+	// the same way encoding/json does it, unless this is set
+	// in which case they are treated as nested blocks named after the type
+	NestEmbeddedStructs bool
+
+	// Nested structs deeper than this are converted
+	// from/to JSON-encoded strings instead of nested blocks (0 = unlimited)
+	MaxDepth int
+
 	mapVarName   string
 	mapValueName string
 	declarations map[string]*FunctionDeclaration
+	models       map[string]string
+	typeStack    []reflect.Type
 }
 
 func (hg *HelperGenerator) init() {
 	hg.declarations = make(map[string]*FunctionDeclaration)
+	hg.typeStack = make([]reflect.Type, 0)
 	if hg.InlineFieldFilterFunc == nil {
 		hg.InlineFieldFilterFunc = acceptAllFilter
 	}
@@ -60,9 +74,295 @@ func (hg *HelperGenerator) renderDeclarations() map[string]string {
 	return m
 }
 
+// Embedded struct is skipped only if both filters reject it,
+// its fields are then filtered individually
+func (hg *HelperGenerator) isPromotedStruct(iface interface{}, sf *reflect.StructField) bool {
+	if hg.NestEmbeddedStructs || !u.IsEmbeddedStruct(sf) {
+		return false
+	}
+	_, inline := hg.InlineFieldFilterFunc(iface, sf, reflect.Struct, &schema.Schema{})
+	_, outline := hg.OutlineFieldFilterFunc(iface, sf, reflect.Struct, &schema.Schema{})
+	return inline || outline
+}
+
+// Field of a struct along with the struct it's declared in (passed to filters),
+// fields of promoted structs are resolved into promoted
+type structField struct {
+	reflect.StructField
+	iface interface{}
+
+	isPromoted bool
+	promoted   []*structField
+	// Accepted by either of filters, i.e. the field takes its key
+	isIncluded bool
+}
+
+// Fields of the struct with promoted ones resolved the same way schemagen does it,
+// i.e. fields of the parent shadow promoted ones, later embedded structs shadow earlier ones.
+// Every generator walks these, so that each key is written only once.
+func (hg *HelperGenerator) structFields(rawType reflect.Type, iface interface{}) []*structField {
+	return hg.resolveStructFields(rawType, iface, []reflect.Type{rawType})
+}
+
+func (hg *HelperGenerator) resolveStructFields(rawType reflect.Type, iface interface{}, typeStack []reflect.Type) []*structField {
+	fields := make([]*structField, 0, rawType.NumField())
+	keys := make(map[string]bool, 0)
+	for i := 0; i < rawType.NumField(); i++ {
+		sf := rawType.Field(i)
+		f := &structField{StructField: sf, iface: iface}
+		if hg.isPromotedStruct(iface, &sf) {
+			structType := u.DereferencePtrType(sf.Type)
+			if err := u.CycleError(typeStack, structType); err != nil {
+				log.Printf("Skipping %s (promoted): %s", sf.Name, err)
+				continue
+			}
+			f.isPromoted = true
+			fields = append(fields, f)
+			continue
+		}
+		fields = append(fields, f)
+		kind := u.DereferencePtrType(sf.Type).Kind()
+		_, inline := hg.InlineFieldFilterFunc(iface, &sf, kind, &schema.Schema{})
+		_, outline := hg.OutlineFieldFilterFunc(iface, &sf, kind, &schema.Schema{})
+		f.isIncluded = inline || outline
+		if f.isIncluded {
+			keys[u.Underscore(sf.Name)] = true
+		}
+	}
+
+	for i := len(fields) - 1; i >= 0; i-- {
+		f := fields[i]
+		if !f.isPromoted {
+			continue
+		}
+		structType := u.DereferencePtrType(f.Type)
+		embeddedIface := reflect.New(structType).Elem().Interface()
+		promoted := hg.resolveStructFields(structType, embeddedIface, append(typeStack, structType))
+		f.promoted = unshadowedFields(promoted, keys)
+		for key := range fieldKeys(f.promoted) {
+			keys[key] = true
+		}
+	}
+
+	return fields
+}
+
+func unshadowedFields(fields []*structField, keys map[string]bool) []*structField {
+	unshadowed := make([]*structField, 0, len(fields))
+	for _, f := range fields {
+		if f.isPromoted {
+			f.promoted = unshadowedFields(f.promoted, keys)
+		} else if f.isIncluded && keys[u.Underscore(f.Name)] {
+			log.Printf("Skipping %s (shadowed)", f.Name)
+			continue
+		}
+		unshadowed = append(unshadowed, f)
+	}
+	return unshadowed
+}
+
+func fieldKeys(fields []*structField) map[string]bool {
+	keys := make(map[string]bool, 0)
+	for _, f := range fields {
+		if f.isPromoted {
+			for key := range fieldKeys(f.promoted) {
+				keys[key] = true
+			}
+		} else if f.isIncluded {
+			keys[u.Underscore(f.Name)] = true
+		}
+	}
+	return keys
+}
+
+func (hg *HelperGenerator) pushType(t reflect.Type) {
+	hg.typeStack = append(hg.typeStack, getRawType(t))
+}
+
+func (hg *HelperGenerator) popType() {
+	hg.typeStack = hg.typeStack[:len(hg.typeStack)-1]
+}
+
+// Nested blocks are cut by MaxDepth depending on the level these are at,
+// rather than the type, so each level needs its own function
+func (hg *HelperGenerator) levelFuncName(funcName string) string {
+	level := len(hg.typeStack)
+	if hg.MaxDepth > 0 && level > 1 {
+		return fmt.Sprintf("%sLevel%d", funcName, level)
+	}
+	return funcName
+}
+
+// Field is converted from/to string if it's one of the built-in types
+// (e.g. free-form JSON or time.Time), otherwise nested block is either
+// checked for cycles or, when it's too deep, converted from/to JSON
+func (hg *HelperGenerator) fieldConversion(kind reflect.Kind, sfType reflect.Type) (u.Conversion, error) {
+	// Unless the filter decided otherwise
+	if kind == u.DereferencePtrType(sfType).Kind() {
+		if conv := u.ConversionForType(sfType); conv != u.NoConversion {
+			return conv, nil
+		}
+	}
+	if !isNestedBlock(kind, sfType) {
+		return u.NoConversion, nil
+	}
+	if hg.MaxDepth > 0 {
+		// Depth limit stops the recursion before a cycle does
+		if len(hg.typeStack) > hg.MaxDepth {
+			return u.JSONConversion, nil
+		}
+		return u.NoConversion, nil
+	}
+	if err := u.CycleError(hg.typeStack, getRawType(sfType)); err != nil {
+		return u.NoConversion, &cycleError{err}
+	}
+	return u.NoConversion, nil
+}
+
+// Cyclic field is left out of the schema (see schemagen),
+// generated helpers say so instead of leaving it out silently
+type cycleError struct {
+	error
+}
+
+// TODO to be placed where the skipped field would be (if it's a cycle)
+func skippedFieldCode(sfName string, err error) string {
+	if _, ok := err.(*cycleError); !ok {
+		return ""
+	}
+	return fmt.Sprintf("// TODO: %s: %s (see MaxDepth)\n", sfName, err)
+}
+
+// Type of the value the SDK stores (e.g. returned by d.Get) for the given kind
+func schemaGoType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Float32, reflect.Float64:
+		return "float64"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	}
+	return "int"
+}
+
+func (hg *HelperGenerator) conversionExpanderForType(conv u.Conversion, t reflect.Type) string {
+	if conv == u.JSONConversion {
+		return hg.jsonExpanderForType(t)
+	}
+
+	funcName := conversionExpanderName(conv, t)
+	body := conversionExpanderBodies[conv]
+	if t.Kind() == reflect.Ptr {
+		body = conversionPtrExpanderBodies[conv]
+	}
+	hg.declarations[funcName] = &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "s string",
+		Outputs:   interfaceFromType(t),
+		FuncBody:  body,
+	}
+	return funcName
+}
+
+func (hg *HelperGenerator) conversionFlattenerForType(conv u.Conversion, t reflect.Type) string {
+	if conv == u.JSONConversion {
+		return hg.jsonFlattenerForType(t)
+	}
+
+	funcName := "flatten" + string(conv)
+	body := conversionFlattenerBodies[conv]
+	if t.Kind() == reflect.Ptr {
+		funcName += "Ptr"
+		body = conversionPtrFlattenerBodies[conv]
+	}
+	hg.declarations[funcName] = &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  funcName,
+		Arguments: hg.InputVarName + " " + interfaceFromType(t),
+		Outputs:   "string",
+		FuncBody:  strings.Replace(body, "{{in}}", hg.InputVarName, -1),
+	}
+	return funcName
+}
+
+// Invalid JSON never gets this far, see validateJSONString (schemagen)
+func (hg *HelperGenerator) jsonExpanderForType(t reflect.Type) string {
+	funcName := jsonFuncName("expand", t)
+	hg.declarations[funcName] = &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "s string",
+		Outputs:   interfaceFromType(t),
+		FuncBody: `var obj ` + interfaceFromType(t) + `
+if s == "" {
+return obj
+}
+json.Unmarshal([]byte(s), &obj)
+return obj`,
+	}
+	return funcName
+}
+
+func (hg *HelperGenerator) jsonFlattenerForType(t reflect.Type) string {
+	funcName := jsonFuncName("flatten", t)
+	hg.declarations[funcName] = &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  funcName,
+		Arguments: hg.InputVarName + " " + interfaceFromType(t),
+		Outputs:   "string",
+		FuncBody: `b, err := json.Marshal(` + hg.InputVarName + `)
+if err != nil || string(b) == "null" {
+return ""
+}
+return string(b)`,
+	}
+	return funcName
+}
+
+func conversionExpanderName(conv u.Conversion, t reflect.Type) string {
+	if conv == u.JSONConversion {
+		return jsonFuncName("expand", t)
+	}
+	if t.Kind() == reflect.Ptr {
+		return "expand" + string(conv) + "Ptr"
+	}
+	return "expand" + string(conv)
+}
+
+func isNestedBlock(kind reflect.Kind, sfType reflect.Type) bool {
+	if kind == reflect.Struct {
+		return true
+	}
+	return kind == reflect.Slice && u.DereferencePtrType(sfType.Elem()).Kind() == reflect.Struct
+}
+
+func jsonFuncName(prefix string, t reflect.Type) string {
+	return prefix + jsonTypeName(t) + "JSON"
+}
+
+func jsonTypeName(t reflect.Type) string {
+	t = u.DereferencePtrType(t)
+	if t.Name() != "" {
+		return t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return jsonTypeName(t.Elem()) + "List"
+	case reflect.Map:
+		return jsonTypeName(t.Elem()) + "Map"
+	}
+	return "Interface"
+}
+
 func emptyConditionForType(inputVarName string, sf *reflect.StructField) (string, error) {
 	leftSide := inputVarName + "." + sf.Name
 
+	if sf.Type.Kind() != reflect.Ptr && u.ConversionForType(sf.Type) == u.TimeConversion {
+		return fmt.Sprintf("!%s.IsZero()", leftSide), nil
+	}
+
 	switch sf.Type.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
@@ -71,7 +371,7 @@ func emptyConditionForType(inputVarName string, sf *reflect.StructField) (string
 		return fmt.Sprintf("%s != %v", leftSide, val.Interface()), nil
 	case reflect.String:
 		return fmt.Sprintf(`%s != ""`, leftSide), nil
-	case reflect.Ptr:
+	case reflect.Ptr, reflect.Interface:
 		return fmt.Sprintf("%s != nil", leftSide), nil
 	case reflect.Slice, reflect.Map:
 		return fmt.Sprintf("len(%s) > 0", leftSide), nil
@@ -92,6 +392,10 @@ func getRawType(t reflect.Type) reflect.Type {
 }
 
 func interfaceFromType(t reflect.Type) string {
+	// e.g. json.RawMessage
+	if t.Name() != "" {
+		return t.String()
+	}
 	ptr := ""
 	slice := ""
 	if t.Kind() == reflect.Slice {
@@ -117,6 +421,59 @@ func rejectAllFilter(iface interface{}, sf *reflect.StructField, k reflect.Kind,
 	return k, false
 }
 
-var funcDeclTpl = template.Must(template.New("func-decl").Parse(`func {{.FuncName}}({{.Arguments}}) {{.Outputs}} {{"{"}}
+var funcDeclTpl = template.Must(template.New("func-decl").Parse(`func {{.FuncName}}({{.Arguments}}) {{if .Outputs}}{{.Outputs}} {{end}}{{"{"}}
 {{.FuncBody}}
 }`))
+
+// Errors are ignored, values are validated by the schema already
+// (see ValidateFunc of converted fields in schemagen)
+var conversionExpanderBodies = map[u.Conversion]string{
+	u.Base64Conversion: `b, _ := base64.StdEncoding.DecodeString(s)
+return b`,
+	u.DurationConversion: `d, _ := time.ParseDuration(s)
+return d`,
+	u.TimeConversion: `t, _ := time.Parse(time.RFC3339, s)
+return t`,
+}
+
+var conversionPtrExpanderBodies = map[u.Conversion]string{
+	u.Base64Conversion: `if s == "" {
+return nil
+}
+b, _ := base64.StdEncoding.DecodeString(s)
+return &b`,
+	u.DurationConversion: `if s == "" {
+return nil
+}
+d, _ := time.ParseDuration(s)
+return &d`,
+	u.TimeConversion: `if s == "" {
+return nil
+}
+t, _ := time.Parse(time.RFC3339, s)
+return &t`,
+}
+
+var conversionFlattenerBodies = map[u.Conversion]string{
+	u.Base64Conversion:   `return base64.StdEncoding.EncodeToString({{in}})`,
+	u.DurationConversion: `return {{in}}.String()`,
+	u.TimeConversion: `if {{in}}.IsZero() {
+return ""
+}
+return {{in}}.Format(time.RFC3339)`,
+}
+
+var conversionPtrFlattenerBodies = map[u.Conversion]string{
+	u.Base64Conversion: `if {{in}} == nil {
+return ""
+}
+return base64.StdEncoding.EncodeToString(*{{in}})`,
+	u.DurationConversion: `if {{in}} == nil {
+return ""
+}
+return {{in}}.String()`,
+	u.TimeConversion: `if {{in}} == nil || {{in}}.IsZero() {
+return ""
+}
+return {{in}}.Format(time.RFC3339)`,
+}
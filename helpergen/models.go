@@ -0,0 +1,269 @@
+package helpergen
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// ModelsFromStruct generates terraform-plugin-framework models (structs with tfsdk tags)
+// of the struct & its nested structs, each with toSDK & fromSDK methods.
+// Null & unknown values are left zero in the SDK struct, fromSDK in turn
+// sets empty outline fields & collections to null.
+// resourcegen's framework backend embeds these in resource & data source models.
+func (hg *HelperGenerator) ModelsFromStruct(iface interface{}) map[string]string {
+	hg.init()
+	hg.models = make(map[string]string)
+	hg.generateModelFromStruct(iface)
+
+	m := hg.renderDeclarations()
+	for name, code := range hg.models {
+		m[name] = code
+	}
+	return m
+}
+
+func ModelName(iface interface{}) string {
+	return modelNameFromType(reflect.TypeOf(iface))
+}
+
+func modelNameFromType(t reflect.Type) string {
+	return getRawType(t).Name() + "Model"
+}
+
+type modelCode struct {
+	decl    string
+	toSDK   string
+	fromSDK string
+}
+
+func (hg *HelperGenerator) generateModelFromStruct(iface interface{}) string {
+	t := reflect.TypeOf(iface)
+	rawType := getRawType(t)
+
+	hg.pushType(t)
+	defer hg.popType()
+
+	name := hg.levelFuncName(modelNameFromType(t))
+	mc := &modelCode{}
+	fields := hg.structFields(rawType, iface)
+	hg.modelFields(fields, "", mc)
+
+	obj := "obj := " + rawType.String() + "{"
+	if inits := hg.embeddedStructInits(fields); inits != "" {
+		obj += "\n" + inits
+	}
+	obj += "}\n"
+
+	hg.models[name] = fmt.Sprintf("type %s struct {\n%s}\n\n", name, mc.decl) +
+		fmt.Sprintf("func (m *%s) toSDK() %s {\n%s%sreturn obj\n}\n\n", name, rawType.String(), obj, mc.toSDK) +
+		fmt.Sprintf("func (m *%s) fromSDK(in %s) {\n%s}", name, rawType.String(), mc.fromSDK)
+
+	return name
+}
+
+func (hg *HelperGenerator) modelFields(fields []*structField, prefix string, mc *modelCode) {
+	for _, f := range fields {
+		if f.isPromoted {
+			structType := u.DereferencePtrType(f.Type)
+			// in is a copy, so nil embedded struct can be replaced with an empty one
+			if f.Type.Kind() == reflect.Ptr {
+				mc.fromSDK += fmt.Sprintf("if in.%s%s == nil {\nin.%s%s = &%s{}\n}\n",
+					prefix, f.Name, prefix, f.Name, structType.String())
+			}
+			hg.modelFields(f.promoted, prefix+f.Name+".", mc)
+			continue
+		}
+		if err := hg.modelField(prefix, f.iface, &f.StructField, mc); err != nil {
+			log.Printf("Skipping %s (model): %s", f.Name, err)
+			mc.decl += skippedFieldCode(f.Name, err)
+		}
+	}
+}
+
+// Field accepted by neither of filters is skipped,
+// one accepted by the outline filter is null when empty
+func (hg *HelperGenerator) modelField(prefix string, iface interface{}, sf *reflect.StructField, mc *modelCode) error {
+	kind, ok := hg.InlineFieldFilterFunc(iface, sf, u.DereferencePtrType(sf.Type).Kind(), &schema.Schema{})
+	outline := false
+	if !ok {
+		kind, ok = hg.OutlineFieldFilterFunc(iface, sf, u.DereferencePtrType(sf.Type).Kind(), &schema.Schema{})
+		if !ok {
+			return fmt.Errorf("Skipping %q (inline & outline filter)", sf.Name)
+		}
+		outline = true
+	}
+
+	conv, err := hg.fieldConversion(kind, sf.Type)
+	if err != nil {
+		return err
+	}
+
+	src := "in." + prefix + sf.Name
+	dst := "obj." + prefix + sf.Name
+	field := "m." + sf.Name
+	isSet := fmt.Sprintf("!%s.IsNull() && !%s.IsUnknown()", field, field)
+	isPtr := sf.Type.Kind() == reflect.Ptr
+
+	var modelType, toSDK, fromSDK string
+	switch {
+	case conv != u.NoConversion:
+		modelType = "types.String"
+		toSDK = fmt.Sprintf("if %s {\n%s = %s(%s.ValueString())\n}\n",
+			isSet, dst, hg.conversionExpanderForType(conv, sf.Type), field)
+		fromSDK = fmt.Sprintf("%s = types.StringNull()\nif v := %s(%s); v != \"\" {\n%s = types.StringValue(v)\n}\n",
+			field, hg.conversionFlattenerForType(conv, sf.Type), src, field)
+	case isPrimitiveKind(kind):
+		valueType, goType := frameworkValueType(kind), u.DereferencePtrType(sf.Type).String()
+		modelType = "types." + valueType
+
+		value := castValue(goType, strings.ToLower(valueType), fmt.Sprintf("%s.Value%s()", field, valueType))
+		if isPtr {
+			toSDK = fmt.Sprintf("if %s {\nv := %s\n%s = &v\n}\n", isSet, value, dst)
+		} else {
+			toSDK = fmt.Sprintf("if %s {\n%s = %s\n}\n", isSet, dst, value)
+		}
+
+		deref := src
+		if isPtr {
+			deref = "*" + src
+		}
+		assignment := fmt.Sprintf("%s = %s\n", field, frameworkValue(kind, goType, deref))
+		if !isPtr && !outline {
+			fromSDK = assignment
+			break
+		}
+		cond, err := emptyConditionForType(strings.TrimSuffix("in."+prefix, "."), sf)
+		if err != nil {
+			return err
+		}
+		fromSDK = fmt.Sprintf("%s = types.%sNull()\nif %s {\n%s}\n", field, valueType, cond, assignment)
+	case kind == reflect.Map:
+		keyType, elemType := sf.Type.Key(), sf.Type.Elem()
+		if sf.Type.Kind() != reflect.Map || keyType.Kind() != reflect.String || !isPrimitiveKind(elemType.Kind()) {
+			return fmt.Errorf("Unable to process: %s %s", sf.Name, sf.Type.String())
+		}
+		valueType := frameworkValueType(elemType.Kind())
+		modelType = "types.Map"
+
+		toSDK = fmt.Sprintf(`if %s {
+%s = make(%s, len(%s.Elements()))
+for k, v := range %s.Elements() {
+%s[%s] = %s
+}
+}
+`, isSet, dst, sf.Type.String(), field, field, dst, castValue(keyType.String(), "string", "k"),
+			castValue(elemType.String(), strings.ToLower(valueType), fmt.Sprintf("v.(types.%s).Value%s()", valueType, valueType)))
+		fromSDK = fmt.Sprintf(`%s = types.MapNull(types.%sType)
+if len(%s) > 0 {
+elems := make(map[string]attr.Value, len(%s))
+for k, v := range %s {
+elems[%s] = %s
+}
+%s = types.MapValueMust(types.%sType, elems)
+}
+`, field, valueType, src, src, src, castValue("string", keyType.String(), "k"),
+			frameworkValue(elemType.Kind(), elemType.String(), "v"), field, valueType)
+	case kind == reflect.Slice && isPrimitiveKind(u.DereferencePtrType(sf.Type.Elem()).Kind()):
+		elemType := u.DereferencePtrType(sf.Type.Elem())
+		elemIsPtr := sf.Type.Elem().Kind() == reflect.Ptr
+		valueType := frameworkValueType(elemType.Kind())
+		// Slices are sets, as in the schema (see schemagen)
+		modelType = "types.Set"
+
+		value := castValue(elemType.String(), strings.ToLower(valueType), fmt.Sprintf("v.(types.%s).Value%s()", valueType, valueType))
+		appended := fmt.Sprintf("%s = append(%s, %s)\n", dst, dst, value)
+		if elemIsPtr {
+			appended = fmt.Sprintf("e := %s\n%s = append(%s, &e)\n", value, dst, dst)
+		}
+		toSDK = fmt.Sprintf("if %s {\n%s = make(%s, 0, len(%s.Elements()))\nfor _, v := range %s.Elements() {\n%s}\n}\n",
+			isSet, dst, sf.Type.String(), field, field, appended)
+
+		appended = fmt.Sprintf("elems = append(elems, %s)\n", frameworkValue(elemType.Kind(), elemType.String(), "v"))
+		if elemIsPtr {
+			appended = fmt.Sprintf("if v != nil {\nelems = append(elems, %s)\n}\n", frameworkValue(elemType.Kind(), elemType.String(), "*v"))
+		}
+		fromSDK = fmt.Sprintf(`%s = types.SetNull(types.%sType)
+if len(%s) > 0 {
+elems := make([]attr.Value, 0, len(%s))
+for _, v := range %s {
+%s}
+%s = types.SetValueMust(types.%sType, elems)
+}
+`, field, valueType, src, src, src, appended, field, valueType)
+	case kind == reflect.Struct:
+		nestedName := hg.generateModelFromStruct(reflect.New(sf.Type).Elem().Interface())
+		modelType = "[]" + nestedName
+
+		if isPtr {
+			toSDK = fmt.Sprintf("if len(%s) > 0 {\nv := %s[0].toSDK()\n%s = &v\n}\n", field, field, dst)
+			fromSDK = fmt.Sprintf("%s = nil\nif %s != nil {\n%s = []%s{{}}\n%s[0].fromSDK(*%s)\n}\n",
+				field, src, field, nestedName, field, src)
+		} else {
+			toSDK = fmt.Sprintf("if len(%s) > 0 {\n%s = %s[0].toSDK()\n}\n", field, dst, field)
+			fromSDK = fmt.Sprintf("%s = []%s{{}}\n%s[0].fromSDK(%s)\n", field, nestedName, field, src)
+		}
+	case kind == reflect.Slice && u.DereferencePtrType(sf.Type.Elem()).Kind() == reflect.Struct:
+		nestedName := hg.generateModelFromStruct(reflect.New(sf.Type).Elem().Interface())
+		modelType = "[]" + nestedName
+
+		if sf.Type.Elem().Kind() == reflect.Ptr {
+			toSDK = fmt.Sprintf("for _, v := range %s {\ne := v.toSDK()\n%s = append(%s, &e)\n}\n", field, dst, dst)
+			fromSDK = fmt.Sprintf("%s = nil\nfor _, v := range %s {\nif v == nil {\ncontinue\n}\nvar nested %s\nnested.fromSDK(*v)\n%s = append(%s, nested)\n}\n",
+				field, src, nestedName, field, field)
+		} else {
+			toSDK = fmt.Sprintf("for _, v := range %s {\n%s = append(%s, v.toSDK())\n}\n", field, dst, dst)
+			fromSDK = fmt.Sprintf("%s = nil\nfor _, v := range %s {\nvar nested %s\nnested.fromSDK(v)\n%s = append(%s, nested)\n}\n",
+				field, src, nestedName, field, field)
+		}
+	default:
+		return fmt.Errorf("Unable to process: %s %s", sf.Name, sf.Type.String())
+	}
+
+	mc.decl += fmt.Sprintf("%s %s `tfsdk:%q`\n", sf.Name, modelType, u.Underscore(sf.Name))
+	mc.toSDK += toSDK
+	mc.fromSDK += fromSDK
+	return nil
+}
+
+func isPrimitiveKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
+		return true
+	}
+	return false
+}
+
+// e.g. Int64 in types.Int64 & ValueInt64()
+func frameworkValueType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "Int64"
+	case reflect.Float32, reflect.Float64:
+		return "Float64"
+	case reflect.Bool:
+		return "Bool"
+	}
+	return "String"
+}
+
+// e.g. types.Int64Value(int64(in.Replicas))
+func frameworkValue(kind reflect.Kind, goType, value string) string {
+	valueType := frameworkValueType(kind)
+	return fmt.Sprintf("types.%sValue(%s)", valueType, castValue(strings.ToLower(valueType), goType, value))
+}
+
+// Conversion is omitted where the types match already
+func castValue(toType, fromType, value string) string {
+	if toType == fromType {
+		return value
+	}
+	return fmt.Sprintf("%s(%s)", toType, value)
+}
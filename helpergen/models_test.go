@@ -0,0 +1,265 @@
+package helpergen
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestModelsFromStruct_primitives(t *testing.T) {
+	type Mode string
+	type SimpleStruct struct {
+		MyInt32   int32
+		MyInt64   int64
+		MyFloat64 float64
+		MyString  *string
+		MyBool    bool
+		MyMode    Mode
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+
+	output := hg.ModelsFromStruct(&SimpleStruct{})
+	expectedOutput := map[string]string{
+		"SimpleStructModel": `type SimpleStructModel struct {
+MyInt32 types.Int64 ` + "`" + `tfsdk:"my_int32"` + "`" + `
+MyInt64 types.Int64 ` + "`" + `tfsdk:"my_int64"` + "`" + `
+MyFloat64 types.Float64 ` + "`" + `tfsdk:"my_float64"` + "`" + `
+MyString types.String ` + "`" + `tfsdk:"my_string"` + "`" + `
+MyBool types.Bool ` + "`" + `tfsdk:"my_bool"` + "`" + `
+MyMode types.String ` + "`" + `tfsdk:"my_mode"` + "`" + `
+}
+
+func (m *SimpleStructModel) toSDK() helpergen.SimpleStruct {
+obj := helpergen.SimpleStruct{}
+if !m.MyInt32.IsNull() && !m.MyInt32.IsUnknown() {
+obj.MyInt32 = int32(m.MyInt32.ValueInt64())
+}
+if !m.MyInt64.IsNull() && !m.MyInt64.IsUnknown() {
+obj.MyInt64 = m.MyInt64.ValueInt64()
+}
+if !m.MyFloat64.IsNull() && !m.MyFloat64.IsUnknown() {
+obj.MyFloat64 = m.MyFloat64.ValueFloat64()
+}
+if !m.MyString.IsNull() && !m.MyString.IsUnknown() {
+v := m.MyString.ValueString()
+obj.MyString = &v
+}
+if !m.MyBool.IsNull() && !m.MyBool.IsUnknown() {
+obj.MyBool = m.MyBool.ValueBool()
+}
+if !m.MyMode.IsNull() && !m.MyMode.IsUnknown() {
+obj.MyMode = helpergen.Mode(m.MyMode.ValueString())
+}
+return obj
+}
+
+func (m *SimpleStructModel) fromSDK(in helpergen.SimpleStruct) {
+m.MyInt32 = types.Int64Value(int64(in.MyInt32))
+m.MyInt64 = types.Int64Value(in.MyInt64)
+m.MyFloat64 = types.Float64Value(in.MyFloat64)
+m.MyString = types.StringNull()
+if in.MyString != nil {
+m.MyString = types.StringValue(*in.MyString)
+}
+m.MyBool = types.BoolValue(in.MyBool)
+m.MyMode = types.StringValue(string(in.MyMode))
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestModelsFromStruct_nested(t *testing.T) {
+	type Port struct {
+		Name   string
+		Number *int32
+	}
+	type ObjectMeta struct {
+		Labels map[string]string
+	}
+	type Service struct {
+		*ObjectMeta
+		Selector []string
+		Primary  Port
+		Fallback *Port
+		Ports    []*Port
+		Timeout  time.Duration
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+		InlineFieldFilterFunc: func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+			return k, sf.Name == "Name" || sf.Name == "Primary"
+		},
+		OutlineFieldFilterFunc: acceptAllFilter,
+	}
+
+	output := hg.ModelsFromStruct(Service{})
+	expectedOutput := map[string]string{
+		"PortModel": `type PortModel struct {
+Name types.String ` + "`" + `tfsdk:"name"` + "`" + `
+Number types.Int64 ` + "`" + `tfsdk:"number"` + "`" + `
+}
+
+func (m *PortModel) toSDK() helpergen.Port {
+obj := helpergen.Port{}
+if !m.Name.IsNull() && !m.Name.IsUnknown() {
+obj.Name = m.Name.ValueString()
+}
+if !m.Number.IsNull() && !m.Number.IsUnknown() {
+v := int32(m.Number.ValueInt64())
+obj.Number = &v
+}
+return obj
+}
+
+func (m *PortModel) fromSDK(in helpergen.Port) {
+m.Name = types.StringValue(in.Name)
+m.Number = types.Int64Null()
+if in.Number != nil {
+m.Number = types.Int64Value(int64(*in.Number))
+}
+}`,
+		"ServiceModel": `type ServiceModel struct {
+Labels types.Map ` + "`" + `tfsdk:"labels"` + "`" + `
+Selector types.Set ` + "`" + `tfsdk:"selector"` + "`" + `
+Primary []PortModel ` + "`" + `tfsdk:"primary"` + "`" + `
+Fallback []PortModel ` + "`" + `tfsdk:"fallback"` + "`" + `
+Ports []PortModel ` + "`" + `tfsdk:"ports"` + "`" + `
+Timeout types.String ` + "`" + `tfsdk:"timeout"` + "`" + `
+}
+
+func (m *ServiceModel) toSDK() helpergen.Service {
+obj := helpergen.Service{
+ObjectMeta: &helpergen.ObjectMeta{},
+}
+if !m.Labels.IsNull() && !m.Labels.IsUnknown() {
+obj.ObjectMeta.Labels = make(map[string]string, len(m.Labels.Elements()))
+for k, v := range m.Labels.Elements() {
+obj.ObjectMeta.Labels[k] = v.(types.String).ValueString()
+}
+}
+if !m.Selector.IsNull() && !m.Selector.IsUnknown() {
+obj.Selector = make([]string, 0, len(m.Selector.Elements()))
+for _, v := range m.Selector.Elements() {
+obj.Selector = append(obj.Selector, v.(types.String).ValueString())
+}
+}
+if len(m.Primary) > 0 {
+obj.Primary = m.Primary[0].toSDK()
+}
+if len(m.Fallback) > 0 {
+v := m.Fallback[0].toSDK()
+obj.Fallback = &v
+}
+for _, v := range m.Ports {
+e := v.toSDK()
+obj.Ports = append(obj.Ports, &e)
+}
+if !m.Timeout.IsNull() && !m.Timeout.IsUnknown() {
+obj.Timeout = expandDuration(m.Timeout.ValueString())
+}
+return obj
+}
+
+func (m *ServiceModel) fromSDK(in helpergen.Service) {
+if in.ObjectMeta == nil {
+in.ObjectMeta = &helpergen.ObjectMeta{}
+}
+m.Labels = types.MapNull(types.StringType)
+if len(in.ObjectMeta.Labels) > 0 {
+elems := make(map[string]attr.Value, len(in.ObjectMeta.Labels))
+for k, v := range in.ObjectMeta.Labels {
+elems[k] = types.StringValue(v)
+}
+m.Labels = types.MapValueMust(types.StringType, elems)
+}
+m.Selector = types.SetNull(types.StringType)
+if len(in.Selector) > 0 {
+elems := make([]attr.Value, 0, len(in.Selector))
+for _, v := range in.Selector {
+elems = append(elems, types.StringValue(v))
+}
+m.Selector = types.SetValueMust(types.StringType, elems)
+}
+m.Primary = []PortModel{{}}
+m.Primary[0].fromSDK(in.Primary)
+m.Fallback = nil
+if in.Fallback != nil {
+m.Fallback = []PortModel{{}}
+m.Fallback[0].fromSDK(*in.Fallback)
+}
+m.Ports = nil
+for _, v := range in.Ports {
+if v == nil {
+continue
+}
+var nested PortModel
+nested.fromSDK(*v)
+m.Ports = append(m.Ports, nested)
+}
+m.Timeout = types.StringNull()
+if v := flattenDuration(in.Timeout); v != "" {
+m.Timeout = types.StringValue(v)
+}
+}`,
+		"expandDuration": `func expandDuration(s string) time.Duration {
+d, _ := time.ParseDuration(s)
+return d
+}`,
+		"flattenDuration": `func flattenDuration(in time.Duration) string {
+return in.String()
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestModelsFromStruct_shadowedField(t *testing.T) {
+	type EmbeddedStruct struct {
+		Name  string
+		Title string
+	}
+	type SimpleStruct struct {
+		EmbeddedStruct
+		Name bool
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+
+	output := hg.ModelsFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"SimpleStructModel": `type SimpleStructModel struct {
+Title types.String ` + "`" + `tfsdk:"title"` + "`" + `
+Name types.Bool ` + "`" + `tfsdk:"name"` + "`" + `
+}
+
+func (m *SimpleStructModel) toSDK() helpergen.SimpleStruct {
+obj := helpergen.SimpleStruct{}
+if !m.Title.IsNull() && !m.Title.IsUnknown() {
+obj.EmbeddedStruct.Title = m.Title.ValueString()
+}
+if !m.Name.IsNull() && !m.Name.IsUnknown() {
+obj.Name = m.Name.ValueBool()
+}
+return obj
+}
+
+func (m *SimpleStructModel) fromSDK(in helpergen.SimpleStruct) {
+m.Title = types.StringValue(in.EmbeddedStruct.Title)
+m.Name = types.BoolValue(in.Name)
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
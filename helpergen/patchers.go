@@ -0,0 +1,281 @@
+package helpergen
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// Generated patchers build JSON Patch operations (PatchOperation, AddOperation,
+// ReplaceOperation & RemoveOperation are expected to exist in the target package)
+// and refer to expanders generated from the same struct via ExpandersFromStruct
+func (hg *HelperGenerator) PatchersFromStruct(iface interface{}) map[string]string {
+	hg.init()
+	hg.generatePatchersFromStruct(iface)
+	return hg.renderDeclarations()
+}
+
+func (hg *HelperGenerator) generatePatchersFromStruct(iface interface{}) string {
+	t := reflect.TypeOf(iface)
+	rawType := getRawType(t)
+
+	hg.pushType(t)
+	defer hg.popType()
+
+	funcName := hg.levelFuncName(patcherFuncNameFromType(t))
+	funcBody := "ops := make([]PatchOperation, 0)\n"
+	funcBody += hg.patcherFields(hg.structFields(rawType, iface))
+	funcBody += "return ops"
+
+	hg.declarations[funcName] = &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "keyPrefix, pathPrefix string, d *schema.ResourceData",
+		Outputs:   "[]PatchOperation",
+		FuncBody:  funcBody,
+	}
+
+	return funcName
+}
+
+func (hg *HelperGenerator) patcherFields(fields []*structField) string {
+	body := ""
+	for _, f := range fields {
+		// Promoted fields share both the key & the path with the parent
+		if f.isPromoted {
+			body += hg.patcherFields(f.promoted)
+			continue
+		}
+		fieldBody, err := hg.patcherField(f.iface, &f.StructField)
+		if err != nil {
+			log.Printf("Skipping %s (patch): %s", f.Name, err)
+			body += skippedFieldCode(f.Name, err)
+			continue
+		}
+		body += fieldBody
+	}
+	return body
+}
+
+func (hg *HelperGenerator) patcherField(iface interface{}, sf *reflect.StructField) (string, error) {
+	kind := u.DereferencePtrType(sf.Type).Kind()
+
+	// Inline fields are always present, so these only ever get replaced,
+	// outline ones may also be added or removed
+	inlineKind, inline := hg.InlineFieldFilterFunc(iface, sf, kind, &schema.Schema{})
+	outlineKind, outline := hg.OutlineFieldFilterFunc(iface, sf, kind, &schema.Schema{})
+	switch {
+	case inline:
+		kind = inlineKind
+	case outline:
+		kind = outlineKind
+	default:
+		return "", fmt.Errorf("Skipping %q (filter)", sf.Name)
+	}
+
+	key := u.Underscore(sf.Name)
+	keyCode := fmt.Sprintf("keyPrefix+%q", key)
+	pathCode := fmt.Sprintf("pathPrefix+%q", "/"+jsonFieldName(sf))
+
+	conv, err := hg.fieldConversion(kind, sf.Type)
+	if err != nil {
+		return "", err
+	}
+	if conv != u.NoConversion {
+		value := fmt.Sprintf("%s(d.Get(%s).(string))", conversionExpanderName(conv, sf.Type), keyCode)
+		return hg.patchOperationCode(inline, keyCode, pathCode, value), nil
+	}
+
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
+		// d.Get only returns int, float64, string or bool
+		rawType := u.DereferencePtrType(sf.Type)
+		schemaType := schemaGoType(kind)
+		value := fmt.Sprintf("d.Get(%s).(%s)", keyCode, schemaType)
+		if rawType.String() != schemaType {
+			if rawType.Kind() != kind {
+				break
+			}
+			value = fmt.Sprintf("%s(%s)", rawType.String(), value)
+		}
+		return hg.patchOperationCode(inline, keyCode, pathCode, value), nil
+	case reflect.Map:
+		hg.declarePatchHelper("diffStringMap")
+		return fmt.Sprintf(`if d.HasChange(%s) {
+oldV, newV := d.GetChange(%s)
+ops = append(ops, diffStringMap(%s, oldV.(map[string]interface{}), newV.(map[string]interface{}))...)
+}
+`, keyCode, keyCode, pathCode), nil
+	case reflect.Slice:
+		// Lists are always replaced as a whole
+		sliceOf := sf.Type.Elem()
+		funcName := ""
+		if u.DereferencePtrType(sliceOf).Kind() == reflect.Struct {
+			funcName = hg.expanderFuncName(sf.Type)
+		} else {
+			funcName = hg.primitiveSliceExpanderForType(u.DereferencePtrType(sliceOf), sf.Type)
+		}
+		if funcName == "" {
+			break
+		}
+		// Slices are TypeSet, unless changed by a filter
+		hg.declarePatchHelper("patchList")
+		value := fmt.Sprintf("%s(patchList(d.Get(%s)))", funcName, keyCode)
+		return hg.patchOperationCode(inline, keyCode, pathCode, value), nil
+	case reflect.Struct:
+		structIface := reflect.New(sf.Type).Elem().Interface()
+		expanderName := hg.expanderFuncName(sf.Type)
+		patcherName := hg.generatePatchersFromStruct(structIface)
+		hg.declarePatchHelper("patchOperation")
+
+		// Block is only patched field by field if it exists on both sides
+		return fmt.Sprintf(`if d.HasChange(%s) {
+oldV, newV := d.GetChange(%s)
+if len(oldV.([]interface{})) > 0 && len(newV.([]interface{})) > 0 {
+ops = append(ops, %s(%s, %s, d)...)
+} else {
+ops = append(ops, patchOperation(%s, %s, d, %s(newV.([]interface{}))))
+}
+}
+`, keyCode, keyCode, patcherName, fmt.Sprintf("keyPrefix+%q", key+".0."), pathCode,
+			keyCode, pathCode, expanderName), nil
+	}
+
+	f := fmt.Sprintf("%s %s\n", sf.Name, sf.Type.String())
+	return "", fmt.Errorf("Unable to process: %s", f)
+}
+
+func (hg *HelperGenerator) patchOperationCode(inline bool, keyCode, pathCode, value string) string {
+	if inline {
+		return fmt.Sprintf(`if d.HasChange(%s) {
+ops = append(ops, &ReplaceOperation{
+Path: %s,
+Value: %s,
+})
+}
+`, keyCode, pathCode, value)
+	}
+
+	hg.declarePatchHelper("patchOperation")
+	return fmt.Sprintf(`if d.HasChange(%s) {
+ops = append(ops, patchOperation(%s, %s, d, %s))
+}
+`, keyCode, keyCode, pathCode, value)
+}
+
+func (hg *HelperGenerator) declarePatchHelper(funcName string) {
+	for _, decl := range patchHelpers[funcName] {
+		hg.declarations[decl.FuncName] = decl
+	}
+}
+
+// Name of the expander as generated by ExpandersFromStruct at the same depth
+func (hg *HelperGenerator) expanderFuncName(t reflect.Type) string {
+	hg.pushType(t)
+	defer hg.popType()
+	return hg.levelFuncName(expanderFuncNameFromType(t))
+}
+
+func jsonFieldName(sf *reflect.StructField) string {
+	name := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
+
+func patcherFuncNameFromType(t reflect.Type) string {
+	// pkg.TypeName
+	parts := strings.Split(t.String(), ".")
+	rawTypeName := parts[1]
+	return "patch" + rawTypeName
+}
+
+var patchOperationFunc = &FunctionDeclaration{
+	FuncName:  "patchOperation",
+	Arguments: "k, path string, d *schema.ResourceData, value interface{}",
+	Outputs:   "PatchOperation",
+	FuncBody: `oldV, newV := d.GetChange(k)
+if isEmptyPatchValue(newV) {
+return &RemoveOperation{Path: path}
+}
+if isEmptyPatchValue(oldV) {
+return &AddOperation{Path: path, Value: value}
+}
+return &ReplaceOperation{Path: path, Value: value}`,
+}
+
+var isEmptyPatchValueFunc = &FunctionDeclaration{
+	FuncName:  "isEmptyPatchValue",
+	Arguments: "v interface{}",
+	Outputs:   "bool",
+	FuncBody: `switch t := v.(type) {
+case nil:
+return true
+case []interface{}:
+return len(t) == 0
+case map[string]interface{}:
+return len(t) == 0
+case *schema.Set:
+return t.Len() == 0
+}
+return v == reflect.Zero(reflect.TypeOf(v)).Interface()`,
+}
+
+var diffStringMapFunc = &FunctionDeclaration{
+	FuncName:  "diffStringMap",
+	Arguments: "pathPrefix string, oldV, newV map[string]interface{}",
+	Outputs:   "[]PatchOperation",
+	FuncBody: `if len(oldV) == 0 {
+return []PatchOperation{&AddOperation{Path: pathPrefix, Value: newV}}
+}
+if len(newV) == 0 {
+return []PatchOperation{&RemoveOperation{Path: pathPrefix}}
+}
+ops := make([]PatchOperation, 0)
+for k := range oldV {
+if _, ok := newV[k]; !ok {
+ops = append(ops, &RemoveOperation{Path: pathPrefix + "/" + escapeJSONPointer(k)})
+}
+}
+for k, v := range newV {
+path := pathPrefix + "/" + escapeJSONPointer(k)
+if oldValue, ok := oldV[k]; !ok {
+ops = append(ops, &AddOperation{Path: path, Value: v.(string)})
+} else if oldValue != v {
+ops = append(ops, &ReplaceOperation{Path: path, Value: v.(string)})
+}
+}
+return ops`,
+}
+
+var escapeJSONPointerFunc = &FunctionDeclaration{
+	FuncName:  "escapeJSONPointer",
+	Arguments: "s string",
+	Outputs:   "string",
+	FuncBody: `s = strings.Replace(s, "~", "~0", -1)
+return strings.Replace(s, "/", "~1", -1)`,
+}
+
+var patchListFunc = &FunctionDeclaration{
+	FuncName:  "patchList",
+	Arguments: "v interface{}",
+	Outputs:   "[]interface{}",
+	FuncBody: `if s, ok := v.(*schema.Set); ok {
+return s.List()
+}
+l, _ := v.([]interface{})
+return l`,
+}
+
+var patchHelpers = map[string][]*FunctionDeclaration{
+	"patchOperation": {patchOperationFunc, isEmptyPatchValueFunc},
+	"patchList":      {patchListFunc},
+	"diffStringMap":  {diffStringMapFunc, escapeJSONPointerFunc},
+}
@@ -0,0 +1,101 @@
+package helpergen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestPatchersFromStruct_basic(t *testing.T) {
+	type NestedStruct struct {
+		NestedInt int `json:"nestedInt"`
+	}
+	type RestartPolicy string
+	type SimpleStruct struct {
+		MyInt      int               `json:"myInt"`
+		MyReplicas int32             `json:"myReplicas"`
+		MyPolicy   RestartPolicy     `json:"myPolicy"`
+		MyString   *string           `json:"myString,omitempty"`
+		MyLabels   map[string]string `json:"labels,omitempty"`
+		MyList     []string          `json:"myList"`
+		MyNested   *NestedStruct     `json:"myNested,omitempty"`
+	}
+	inlineFilter := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Type.Kind() != reflect.Ptr && sf.Type.Kind() != reflect.Map
+	}
+	outlineFilter := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Type.Kind() == reflect.Ptr || sf.Type.Kind() == reflect.Map
+	}
+	hg := &HelperGenerator{
+		InputVarName:           "cfg",
+		OutputVarName:          "obj",
+		InlineFieldFilterFunc:  inlineFilter,
+		OutlineFieldFilterFunc: outlineFilter,
+	}
+
+	output := hg.PatchersFromStruct(SimpleStruct{})
+	expectedOutput := map[string]string{
+		"patchSimpleStruct": `func patchSimpleStruct(keyPrefix, pathPrefix string, d *schema.ResourceData) []PatchOperation {
+ops := make([]PatchOperation, 0)
+if d.HasChange(keyPrefix+"my_int") {
+ops = append(ops, &ReplaceOperation{
+Path: pathPrefix+"/myInt",
+Value: d.Get(keyPrefix+"my_int").(int),
+})
+}
+if d.HasChange(keyPrefix+"my_replicas") {
+ops = append(ops, &ReplaceOperation{
+Path: pathPrefix+"/myReplicas",
+Value: int32(d.Get(keyPrefix+"my_replicas").(int)),
+})
+}
+if d.HasChange(keyPrefix+"my_policy") {
+ops = append(ops, &ReplaceOperation{
+Path: pathPrefix+"/myPolicy",
+Value: helpergen.RestartPolicy(d.Get(keyPrefix+"my_policy").(string)),
+})
+}
+if d.HasChange(keyPrefix+"my_string") {
+ops = append(ops, patchOperation(keyPrefix+"my_string", pathPrefix+"/myString", d, d.Get(keyPrefix+"my_string").(string)))
+}
+if d.HasChange(keyPrefix+"my_labels") {
+oldV, newV := d.GetChange(keyPrefix+"my_labels")
+ops = append(ops, diffStringMap(pathPrefix+"/labels", oldV.(map[string]interface{}), newV.(map[string]interface{}))...)
+}
+if d.HasChange(keyPrefix+"my_list") {
+ops = append(ops, &ReplaceOperation{
+Path: pathPrefix+"/myList",
+Value: sliceOfString(patchList(d.Get(keyPrefix+"my_list"))),
+})
+}
+if d.HasChange(keyPrefix+"my_nested") {
+oldV, newV := d.GetChange(keyPrefix+"my_nested")
+if len(oldV.([]interface{})) > 0 && len(newV.([]interface{})) > 0 {
+ops = append(ops, patchNestedStruct(keyPrefix+"my_nested.0.", pathPrefix+"/myNested", d)...)
+} else {
+ops = append(ops, patchOperation(keyPrefix+"my_nested", pathPrefix+"/myNested", d, expandNestedStruct(newV.([]interface{}))))
+}
+}
+return ops
+}`,
+		"patchNestedStruct": `func patchNestedStruct(keyPrefix, pathPrefix string, d *schema.ResourceData) []PatchOperation {
+ops := make([]PatchOperation, 0)
+if d.HasChange(keyPrefix+"nested_int") {
+ops = append(ops, &ReplaceOperation{
+Path: pathPrefix+"/nestedInt",
+Value: d.Get(keyPrefix+"nested_int").(int),
+})
+}
+return ops
+}`,
+		"patchOperation":    "func patchOperation(k, path string, d *schema.ResourceData, value interface{}) PatchOperation {\n" + patchOperationFunc.FuncBody + "\n}",
+		"isEmptyPatchValue": "func isEmptyPatchValue(v interface{}) bool {\n" + isEmptyPatchValueFunc.FuncBody + "\n}",
+		"patchList":         "func patchList(v interface{}) []interface{} {\n" + patchListFunc.FuncBody + "\n}",
+		"diffStringMap":     "func diffStringMap(pathPrefix string, oldV, newV map[string]interface{}) []PatchOperation {\n" + diffStringMapFunc.FuncBody + "\n}",
+		"escapeJSONPointer": "func escapeJSONPointer(s string) string {\n" + escapeJSONPointerFunc.FuncBody + "\n}",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
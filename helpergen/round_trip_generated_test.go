@@ -0,0 +1,325 @@
+// Code generated by TestRoundTripTestsFromStruct_generated. DO NOT EDIT.
+
+package helpergen_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/helpergen"
+)
+
+var roundTripStructSchema = map[string]*schema.Schema{
+	"my_bool": {
+		Type:     schema.TypeBool,
+		Required: true,
+	},
+	"my_duration": {
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validateDuration,
+	},
+	"my_float": {
+		Type:     schema.TypeFloat,
+		Required: true,
+	},
+	"my_int": {
+		Type:     schema.TypeInt,
+		Required: true,
+	},
+	"my_int32": {
+		Type:     schema.TypeInt,
+		Required: true,
+	},
+	"my_int_list": {
+		Type:     schema.TypeSet,
+		Required: true,
+		Elem:     &schema.Schema{Type: schema.TypeInt},
+	},
+	"my_items": {
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem:     roundTripItemSchema(),
+	},
+	"my_list": {
+		Type:     schema.TypeSet,
+		Required: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+		Set:      schema.HashString,
+	},
+	"my_map": {
+		Type:     schema.TypeMap,
+		Required: true,
+	},
+	"my_nested": {
+		Type:     schema.TypeList,
+		Required: true,
+		MaxItems: 1,
+		Elem:     roundTripNestedSchema(),
+	},
+	"my_policy": {
+		Type:     schema.TypeString,
+		Required: true,
+	},
+	"my_ptr_int": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"my_ptr_string": {
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"my_string": {
+		Type:     schema.TypeString,
+		Required: true,
+	},
+}
+
+func roundTripItemSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"item_string": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+
+func roundTripNestedSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"nested_int": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"nested_string": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func validateDuration(v interface{}, k string) (ws []string, es []error) {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%q: %s", k, err))
+	}
+	return
+}
+
+func flattenDuration(in time.Duration) string {
+	return in.String()
+}
+
+func flattenMapOfString(m map[string]string) map[string]interface{} {
+	att := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		att[k] = v
+	}
+	return att
+}
+
+func flattenRoundTripItem(in []helpergen.RoundTripItem) []interface{} {
+	att := make([]interface{}, len(in), len(in))
+	for i, n := range in {
+		m := make(map[string]interface{})
+		m["item_string"] = n.ItemString
+		att[i] = m
+	}
+	return att
+}
+
+func flattenRoundTripNested(in *helpergen.RoundTripNested) []interface{} {
+	att := make(map[string]interface{})
+	att["nested_int"] = in.NestedInt
+	if in.NestedString != "" {
+		att["nested_string"] = in.NestedString
+	}
+	return []interface{}{att}
+}
+
+func flattenRoundTripStruct(in helpergen.RoundTripStruct) []interface{} {
+	att := make(map[string]interface{})
+	att["my_int"] = in.MyInt
+	att["my_int32"] = in.MyInt32
+	att["my_float"] = in.MyFloat
+	att["my_bool"] = in.MyBool
+	att["my_string"] = in.MyString
+	att["my_policy"] = in.MyPolicy
+	att["my_map"] = flattenMapOfString(in.MyMap)
+	att["my_list"] = flattenSliceOfString(in.MyList)
+	att["my_int_list"] = flattenSliceOfInt32(in.MyIntList)
+	att["my_duration"] = flattenDuration(in.MyDuration)
+	att["my_nested"] = flattenRoundTripNested(in.MyNested)
+	if in.MyPtrString != nil {
+		att["my_ptr_string"] = *in.MyPtrString
+	}
+	if in.MyPtrInt != nil {
+		att["my_ptr_int"] = *in.MyPtrInt
+	}
+	if len(in.MyItems) > 0 {
+		att["my_items"] = flattenRoundTripItem(in.MyItems)
+	}
+	return []interface{}{att}
+}
+
+func flattenSliceOfInt32(l []int32) []interface{} {
+	att := make([]interface{}, len(l))
+	for i, v := range l {
+		att[i] = int(v)
+	}
+	return att
+}
+
+func flattenSliceOfString(l []string) []interface{} {
+	att := make([]interface{}, len(l))
+	for i, v := range l {
+		att[i] = v
+	}
+	return att
+}
+
+func expandDuration(s string) time.Duration {
+	d, _ := time.ParseDuration(s)
+	return d
+}
+
+func expandRoundTripItem(l []interface{}) []helpergen.RoundTripItem {
+	if len(l) == 0 || l[0] == nil {
+		return []helpergen.RoundTripItem{}
+	}
+	obj := make([]helpergen.RoundTripItem, len(l), len(l))
+	for i, n := range l {
+		in := n.(map[string]interface{})
+		obj[i] = helpergen.RoundTripItem{}
+		if v, ok := in["item_string"].(string); ok {
+			obj[i].ItemString = v
+		}
+	}
+	return obj
+}
+
+func expandRoundTripNested(l []interface{}) *helpergen.RoundTripNested {
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+	in := l[0].(map[string]interface{})
+	obj := &helpergen.RoundTripNested{}
+	if v, ok := in["nested_int"].(int); ok {
+		obj.NestedInt = v
+	}
+	if v, ok := in["nested_string"].(string); ok && v != "" {
+		obj.NestedString = v
+	}
+	return obj
+}
+
+func expandRoundTripStruct(l []interface{}) helpergen.RoundTripStruct {
+	if len(l) == 0 || l[0] == nil {
+		return helpergen.RoundTripStruct{}
+	}
+	in := l[0].(map[string]interface{})
+	obj := helpergen.RoundTripStruct{}
+	if v, ok := in["my_int"].(int); ok {
+		obj.MyInt = v
+	}
+	if v, ok := in["my_int32"].(int); ok {
+		obj.MyInt32 = int32(v)
+	}
+	if v, ok := in["my_float"].(float64); ok {
+		obj.MyFloat = v
+	}
+	if v, ok := in["my_bool"].(bool); ok {
+		obj.MyBool = v
+	}
+	if v, ok := in["my_string"].(string); ok {
+		obj.MyString = v
+	}
+	if v, ok := in["my_policy"].(string); ok {
+		obj.MyPolicy = helpergen.RoundTripPolicy(v)
+	}
+	if v, ok := in["my_map"].(map[string]interface{}); ok {
+		obj.MyMap = expandStringMap(v)
+	}
+	if v, ok := listValue(in["my_list"]); ok {
+		obj.MyList = sliceOfString(v)
+	}
+	if v, ok := listValue(in["my_int_list"]); ok {
+		obj.MyIntList = expandSliceOfInt32(v)
+	}
+	if v, ok := in["my_duration"].(string); ok {
+		obj.MyDuration = expandDuration(v)
+	}
+	if v, ok := in["my_nested"].([]interface{}); ok && len(v) > 0 {
+		obj.MyNested = expandRoundTripNested(v)
+	}
+	if v, ok := in["my_ptr_string"].(string); ok && v != "" {
+		obj.MyPtrString = ptrToString(v)
+	}
+	if v, ok := in["my_ptr_int"].(int); ok && v != 0 {
+		obj.MyPtrInt = ptrToInt32(int32(v))
+	}
+	if v, ok := listValue(in["my_items"]); ok && len(v) > 0 {
+		obj.MyItems = expandRoundTripItem(v)
+	}
+	return obj
+}
+
+func expandSliceOfInt32(l []interface{}) []int32 {
+	s := make([]int32, len(l))
+	for i, v := range l {
+		s[i] = int32(v.(int))
+	}
+	return s
+}
+
+func listValue(v interface{}) ([]interface{}, bool) {
+	if s, ok := v.(*schema.Set); ok {
+		return s.List(), true
+	}
+	l, ok := v.([]interface{})
+	return l, ok
+}
+
+func TestExpandFlattenRoundTripStruct(t *testing.T) {
+	in := helpergen.RoundTripStruct{
+		MyInt:       1,
+		MyInt32:     1,
+		MyFloat:     1.5,
+		MyBool:      true,
+		MyString:    "tf-acc-test",
+		MyPolicy:    "tf-acc-test",
+		MyPtrString: &[]string{"tf-acc-test"}[0],
+		MyPtrInt:    &[]int32{1}[0],
+		MyMap:       map[string]string{"foo": "bar"},
+		MyList:      []string{"tf-acc-test"},
+		MyIntList:   []int32{1},
+		MyDuration:  5 * time.Second,
+		MyNested: &helpergen.RoundTripNested{
+			NestedInt:    1,
+			NestedString: "tf-acc-test",
+		},
+		MyItems: []helpergen.RoundTripItem{helpergen.RoundTripItem{
+			ItemString: "tf-acc-test",
+		}},
+	}
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+		"value": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem:     &schema.Resource{Schema: roundTripStructSchema},
+		},
+	}, map[string]interface{}{})
+	if err := d.Set("value", flattenRoundTripStruct(in)); err != nil {
+		t.Fatal(err)
+	}
+	out := expandRoundTripStruct(d.Get("value").([]interface{}))
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("Round trip failed.\nExpected: %#v\nGiven:    %#v", in, out)
+	}
+}
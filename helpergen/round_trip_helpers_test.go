@@ -0,0 +1,35 @@
+package helpergen_test
+
+// Helpers generated code expects to exist in the target package
+
+func sliceOfString(l []interface{}) []string {
+	s := make([]string, len(l))
+	for i, v := range l {
+		s[i] = v.(string)
+	}
+	return s
+}
+
+func sliceOfInt(l []interface{}) []int {
+	s := make([]int, len(l))
+	for i, v := range l {
+		s[i] = v.(int)
+	}
+	return s
+}
+
+func expandStringMap(m map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v.(string)
+	}
+	return result
+}
+
+func ptrToString(s string) *string {
+	return &s
+}
+
+func ptrToInt32(i int32) *int32 {
+	return &i
+}
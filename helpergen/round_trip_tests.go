@@ -0,0 +1,185 @@
+package helpergen
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// Generated test populates the struct with non-zero values and checks
+// that expander & flattener are inverse to each other, with the flattened value
+// stored & read back via ResourceData, so it's converted the way the SDK does it.
+// schemaCode refers to the generated schema of the struct, e.g. podSpecSchema
+func (hg *HelperGenerator) RoundTripTestsFromStruct(iface interface{}, schemaCode string) map[string]string {
+	hg.init()
+	hg.generateRoundTripTest(iface, schemaCode)
+	return hg.renderDeclarations()
+}
+
+func (hg *HelperGenerator) generateRoundTripTest(iface interface{}, schemaCode string) string {
+	t := reflect.TypeOf(iface)
+
+	// pkg.TypeName
+	parts := strings.Split(t.String(), ".")
+	funcName := "TestExpandFlatten" + parts[1]
+
+	funcBody := "in := " + hg.sampleStructLiteral(t) + "\n"
+	funcBody += fmt.Sprintf(`d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+"value": {
+Type: schema.TypeList,
+Optional: true,
+MaxItems: 1,
+Elem: &schema.Resource{Schema: %s},
+},
+}, map[string]interface{}{})
+if err := d.Set("value", %s(in)); err != nil {
+t.Fatal(err)
+}
+out := %s(d.Get("value").([]interface{}))
+`, schemaCode, flattenerFuncNameFromType(t), expanderFuncNameFromType(t))
+	funcBody += `if !reflect.DeepEqual(in, out) {
+t.Fatalf("Round trip failed.\nExpected: %#v\nGiven:    %#v", in, out)
+}`
+
+	hg.declarations[funcName] = &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "t *testing.T",
+		FuncBody:  funcBody,
+	}
+
+	return funcName
+}
+
+func (hg *HelperGenerator) sampleStructLiteral(t reflect.Type) string {
+	hg.pushType(t)
+	defer hg.popType()
+
+	rawType := u.DereferencePtrType(t)
+	iface := reflect.New(t).Elem().Interface()
+
+	ptr := ""
+	if t.Kind() == reflect.Ptr {
+		ptr = "&"
+	}
+	return ptr + rawType.String() + "{\n" + hg.sampleFields(hg.structFields(rawType, iface)) + "}"
+}
+
+func (hg *HelperGenerator) sampleFields(fields []*structField) string {
+	body := ""
+	for _, f := range fields {
+		sf := f.StructField
+		if f.isPromoted {
+			structType := u.DereferencePtrType(sf.Type)
+			ptr := ""
+			if sf.Type.Kind() == reflect.Ptr {
+				ptr = "&"
+			}
+			body += fmt.Sprintf("%s: %s%s{\n%s},\n", sf.Name, ptr, structType.String(),
+				hg.sampleFields(f.promoted))
+			continue
+		}
+
+		kind := u.DereferencePtrType(sf.Type).Kind()
+		inlineKind, inline := hg.InlineFieldFilterFunc(f.iface, &sf, kind, &schema.Schema{})
+		outlineKind, outline := hg.OutlineFieldFilterFunc(f.iface, &sf, kind, &schema.Schema{})
+		switch {
+		case inline:
+			kind = inlineKind
+		case outline:
+			kind = outlineKind
+		default:
+			continue
+		}
+
+		value, err := hg.sampleValue(kind, sf.Type)
+		if err != nil {
+			log.Printf("Leaving %s empty (round trip): %s", sf.Name, err)
+			continue
+		}
+		body += fmt.Sprintf("%s: %s,\n", sf.Name, value)
+	}
+	return body
+}
+
+func (hg *HelperGenerator) sampleValue(kind reflect.Kind, t reflect.Type) (string, error) {
+	conv, err := hg.fieldConversion(kind, t)
+	if err != nil {
+		return "", err
+	}
+	if conv != u.NoConversion {
+		value, err := sampleConvertedValue(conv, u.DereferencePtrType(t))
+		if err != nil {
+			return "", err
+		}
+		return samplePtrValue(t, value), nil
+	}
+
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return samplePtrValue(t, "1"), nil
+	case reflect.Float32, reflect.Float64:
+		return samplePtrValue(t, "1.5"), nil
+	case reflect.String:
+		return samplePtrValue(t, `"tf-acc-test"`), nil
+	case reflect.Bool:
+		return samplePtrValue(t, "true"), nil
+	case reflect.Map:
+		if t.Elem().Kind() == reflect.String {
+			return fmt.Sprintf(`%s{"foo": "bar"}`, t.String()), nil
+		}
+	case reflect.Slice:
+		sliceOf := t.Elem()
+		if u.DereferencePtrType(sliceOf).Kind() == reflect.Struct {
+			return fmt.Sprintf("%s{%s}", t.String(), hg.sampleStructLiteral(sliceOf)), nil
+		}
+		value, err := hg.sampleValue(u.DereferencePtrType(sliceOf).Kind(), sliceOf)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s{%s}", t.String(), value), nil
+	case reflect.Struct:
+		return hg.sampleStructLiteral(t), nil
+	}
+
+	return "", fmt.Errorf("No sample value for %s", t.String())
+}
+
+func sampleConvertedValue(conv u.Conversion, t reflect.Type) (string, error) {
+	switch conv {
+	case u.Base64Conversion:
+		return fmt.Sprintf(`%s("tf-acc-test")`, t.String()), nil
+	case u.DurationConversion:
+		return "5 * time.Second", nil
+	case u.TimeConversion:
+		return "time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)", nil
+	case u.JSONConversion:
+		// Structs beyond MaxDepth are left empty
+		switch t.Kind() {
+		case reflect.Interface, reflect.Map:
+			return `map[string]interface{}{"foo": "bar"}`, nil
+		case reflect.Slice:
+			if u.DereferencePtrType(t.Elem()).Kind() == reflect.Struct {
+				break
+			}
+			if t.Elem().Kind() == reflect.Uint8 {
+				return fmt.Sprintf(`%s("{\"foo\":\"bar\"}")`, t.String()), nil
+			}
+			return fmt.Sprintf(`%s{"foo"}`, t.String()), nil
+		}
+	}
+	return "", fmt.Errorf("No sample value for %s", t.String())
+}
+
+// Pointer to a literal (e.g. *string) is taken via a single-element slice
+func samplePtrValue(t reflect.Type, value string) string {
+	if t.Kind() != reflect.Ptr {
+		return value
+	}
+	return fmt.Sprintf("&[]%s{%s}[0]", t.Elem().String(), value)
+}
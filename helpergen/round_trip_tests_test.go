@@ -0,0 +1,197 @@
+package helpergen
+
+import (
+	"bytes"
+	"flag"
+	"go/format"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+func TestRoundTripTestsFromStruct_basic(t *testing.T) {
+	type NestedStruct struct {
+		NestedInt int
+	}
+	type SimpleStruct struct {
+		MyInt      int
+		MyString   *string `api:"optional"`
+		MyMap      map[string]string
+		MyList     []string
+		MyDuration time.Duration
+		MyNested   *NestedStruct
+		MySkipped  string `api:"-"`
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	hg.InlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") == ""
+	}
+	hg.OutlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") == "optional"
+	}
+
+	output := hg.RoundTripTestsFromStruct(SimpleStruct{}, "simpleStructSchema()")
+	expectedOutput := map[string]string{
+		"TestExpandFlattenSimpleStruct": `func TestExpandFlattenSimpleStruct(t *testing.T) {
+in := helpergen.SimpleStruct{
+MyInt: 1,
+MyString: &[]string{"tf-acc-test"}[0],
+MyMap: map[string]string{"foo": "bar"},
+MyList: []string{"tf-acc-test"},
+MyDuration: 5 * time.Second,
+MyNested: &helpergen.NestedStruct{
+NestedInt: 1,
+},
+}
+d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{
+"value": {
+Type: schema.TypeList,
+Optional: true,
+MaxItems: 1,
+Elem: &schema.Resource{Schema: simpleStructSchema()},
+},
+}, map[string]interface{}{})
+if err := d.Set("value", flattenSimpleStruct(in)); err != nil {
+t.Fatal(err)
+}
+out := expandSimpleStruct(d.Get("value").([]interface{}))
+if !reflect.DeepEqual(in, out) {
+t.Fatalf("Round trip failed.\nExpected: %#v\nGiven:    %#v", in, out)
+}
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+var update = flag.Bool("update", false, "update round_trip_generated_test.go")
+
+type RoundTripPolicy string
+
+type RoundTripNested struct {
+	NestedInt    int
+	NestedString string `api:"optional"`
+}
+
+type RoundTripItem struct {
+	ItemString string
+}
+
+type RoundTripStruct struct {
+	MyInt       int
+	MyInt32     int32
+	MyFloat     float64
+	MyBool      bool
+	MyString    string
+	MyPolicy    RoundTripPolicy
+	MyPtrString *string `api:"optional"`
+	MyPtrInt    *int32  `api:"optional"`
+	MyMap       map[string]string
+	MyList      []string
+	MyIntList   []int32
+	MyDuration  time.Duration
+	MyNested    *RoundTripNested
+	MyItems     []RoundTripItem `api:"optional"`
+}
+
+// The generated file is compiled & run as part of this package's tests
+func TestRoundTripTestsFromStruct_generated(t *testing.T) {
+	filter := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		if sf.Tag.Get("api") == "optional" {
+			s.Optional = true
+		} else {
+			s.Required = true
+		}
+		return k, true
+	}
+	sg := &schemagen.SchemaGenerator{
+		FilterFunc:        filter,
+		SharedSchemaFuncs: true,
+	}
+	fields := sg.FromStruct(RoundTripStruct{})
+
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	hg.InlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, sf.Tag.Get("api") == ""
+	}
+	hg.OutlineFieldFilterFunc = func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		s.Optional = true
+		return k, sf.Tag.Get("api") == "optional"
+	}
+
+	buf := &bytes.Buffer{}
+	err := roundTripFileTpl.Execute(buf, map[string]map[string]string{
+		"Fields":     fields,
+		"Functions":  sg.SchemaFunctions(),
+		"Flatteners": hg.FlattenersFromStruct(RoundTripStruct{}),
+		"Expanders":  hg.ExpandersFromStruct(RoundTripStruct{}),
+		"Tests":      hg.RoundTripTestsFromStruct(RoundTripStruct{}, "roundTripStructSchema"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := format.Source(buf.Bytes())
+	if err != nil {
+		t.Fatalf("%s\n\n%s", err, buf.String())
+	}
+
+	filename := "round_trip_generated_test.go"
+	if *update {
+		if err := ioutil.WriteFile(filename, output, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectedOutput, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(output) != string(expectedOutput) {
+		t.Fatalf("%s is outdated (go test -run %s -update)\n\nExpected: %s\n\nGiven:    %s",
+			filename, t.Name(), expectedOutput, output)
+	}
+}
+
+var roundTripFileTpl = template.Must(template.New("round-trip-file").Parse(`// Code generated by TestRoundTripTestsFromStruct_generated. DO NOT EDIT.
+
+package helpergen_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/helpergen"
+)
+
+var roundTripStructSchema = map[string]*schema.Schema{
+{{- range $name, $schema := .Fields}}
+	"{{ $name }}": {{ $schema }},
+{{- end}}
+}
+{{range $name, $definition := .Functions}}
+{{ $definition }}
+{{end}}
+{{range $name, $definition := .Flatteners}}
+{{ $definition }}
+{{end}}
+{{range $name, $definition := .Expanders}}
+{{ $definition }}
+{{end}}
+{{range $name, $definition := .Tests}}
+{{ $definition }}
+{{end}}
+`))
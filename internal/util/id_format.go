@@ -0,0 +1,63 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// e.g. {metadata.0.namespace}/{metadata.0.name}
+type IDFormat struct {
+	Format    string
+	Separator string
+	Parts     []*IDPart
+}
+
+type IDPart struct {
+	// Attribute path, e.g. metadata.0.name
+	Path string
+	// Last segment of the path, e.g. name
+	Name string
+}
+
+var idPlaceholderRegexp = regexp.MustCompile(`\{([^}]+)\}`)
+
+func ParseIDFormat(format string) (*IDFormat, error) {
+	id := &IDFormat{Format: format}
+
+	matches := idPlaceholderRegexp.FindAllStringSubmatchIndex(format, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("No placeholders found in ID format %q", format)
+	}
+	if matches[0][0] != 0 || matches[len(matches)-1][1] != len(format) {
+		return nil, fmt.Errorf("ID format %q must start and end with a placeholder", format)
+	}
+
+	for i, m := range matches {
+		if i > 0 {
+			sep := format[matches[i-1][1]:m[0]]
+			if sep == "" || (id.Separator != "" && sep != id.Separator) {
+				return nil, fmt.Errorf("ID format %q must use a single non-empty separator", format)
+			}
+			id.Separator = sep
+		}
+
+		path := format[m[2]:m[3]]
+		pathParts := strings.Split(path, ".")
+		id.Parts = append(id.Parts, &IDPart{
+			Path: path,
+			Name: pathParts[len(pathParts)-1],
+		})
+	}
+
+	return id, nil
+}
+
+// Placeholders replaced with their names, e.g. namespace/name
+func (id *IDFormat) Example() string {
+	names := make([]string, len(id.Parts))
+	for i, p := range id.Parts {
+		names[i] = p.Name
+	}
+	return strings.Join(names, id.Separator)
+}
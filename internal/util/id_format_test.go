@@ -0,0 +1,42 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIDFormat(t *testing.T) {
+	id, err := ParseIDFormat("{metadata.0.namespace}/{metadata.0.name}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedID := &IDFormat{
+		Format:    "{metadata.0.namespace}/{metadata.0.name}",
+		Separator: "/",
+		Parts: []*IDPart{
+			{Path: "metadata.0.namespace", Name: "namespace"},
+			{Path: "metadata.0.name", Name: "name"},
+		},
+	}
+	if !reflect.DeepEqual(id, expectedID) {
+		t.Fatalf("Expected: %#v\n\nGiven: %#v\n", expectedID, id)
+	}
+	if id.Example() != "namespace/name" {
+		t.Fatalf("Unexpected example: %q", id.Example())
+	}
+}
+
+func TestParseIDFormat_invalid(t *testing.T) {
+	formats := []string{
+		"name",
+		"id-{name}",
+		"{namespace}{name}",
+		"{project}/{region}:{name}",
+	}
+	for _, format := range formats {
+		_, err := ParseIDFormat(format)
+		if err == nil {
+			t.Fatalf("Expected error for ID format %q", format)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package util
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 )
 
 func Underscore(name string) string {
@@ -22,6 +25,23 @@ func Underscore(name string) string {
 	return strings.ToLower(strings.Join(words, "_"))
 }
 
+func Camelize(name string) string {
+	words := strings.Split(name, "_")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, "")
+}
+
+func LowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
 func DereferencePtrType(t reflect.Type) reflect.Type {
 	kind := t.Kind()
 	if kind == reflect.Ptr {
@@ -37,3 +57,78 @@ func DereferencePtrValue(v reflect.Value) reflect.Value {
 	}
 	return v
 }
+
+func IsEmbeddedStruct(sf *reflect.StructField) bool {
+	return sf.Anonymous && DereferencePtrType(sf.Type).Kind() == reflect.Struct
+}
+
+func CycleError(typeStack []reflect.Type, t reflect.Type) error {
+	for i, st := range typeStack {
+		if st == t {
+			names := make([]string, 0)
+			for _, ct := range typeStack[i:] {
+				names = append(names, ct.String())
+			}
+			names = append(names, t.String())
+			return fmt.Errorf("Cycle detected: %s", strings.Join(names, " -> "))
+		}
+	}
+	return nil
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// IsFreeFormJSON returns true for types which can hold any JSON,
+// such as interface{}, json.RawMessage or runtime.RawExtension
+// (including slices and maps of these)
+func IsFreeFormJSON(t reflect.Type) bool {
+	t = DereferencePtrType(t)
+	if t == rawMessageType {
+		return true
+	}
+	// k8s.io/apimachinery/pkg/runtime (k8s.io/kubernetes/pkg/runtime in older versions)
+	if t.Name() == "RawExtension" && strings.HasSuffix(t.PkgPath(), "/runtime") {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	case reflect.Slice, reflect.Map:
+		return IsFreeFormJSON(t.Elem())
+	}
+	return false
+}
+
+// Conversion describes how a Go type is represented in a TypeString field
+type Conversion string
+
+const (
+	NoConversion       Conversion = ""
+	JSONConversion     Conversion = "JSON"
+	Base64Conversion   Conversion = "Base64"
+	DurationConversion Conversion = "Duration"
+	TimeConversion     Conversion = "Time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+func ConversionForType(t reflect.Type) Conversion {
+	if IsFreeFormJSON(t) {
+		return JSONConversion
+	}
+
+	t = DereferencePtrType(t)
+	switch {
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return Base64Conversion
+	case t == durationType:
+		return DurationConversion
+	case t == timeType:
+		return TimeConversion
+	}
+	return NoConversion
+}
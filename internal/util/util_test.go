@@ -1,7 +1,11 @@
 package util
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestUnderscore(t *testing.T) {
@@ -20,3 +24,82 @@ func TestUnderscore(t *testing.T) {
 		}
 	}
 }
+
+func TestCamelize(t *testing.T) {
+	testCases := map[string]string{
+		"kubernetes_config_map": "KubernetesConfigMap",
+		"name":                  "Name",
+		"aws_s3_bucket":         "AwsS3Bucket",
+	}
+
+	for from, to := range testCases {
+		converted := Camelize(from)
+		if converted != to {
+			t.Fatalf("Expected %q after conversion, given: %q", to, converted)
+		}
+	}
+}
+
+func TestCycleError(t *testing.T) {
+	type First struct{}
+	type Second struct{}
+	type Third struct{}
+
+	first := reflect.TypeOf(First{})
+	second := reflect.TypeOf(Second{})
+	third := reflect.TypeOf(Third{})
+	stack := []reflect.Type{first, second, third}
+
+	err := CycleError(stack, second)
+	expectedErr := "Cycle detected: util.Second -> util.Third -> util.Second"
+	if err == nil || err.Error() != expectedErr {
+		t.Fatalf("Expected error %q, given: %v", expectedErr, err)
+	}
+
+	err = CycleError(stack[:1], second)
+	if err != nil {
+		t.Fatalf("Expected no error, given: %s", err)
+	}
+}
+
+func TestIsFreeFormJSON(t *testing.T) {
+	type Struct struct{}
+	testCases := map[reflect.Type]bool{
+		reflect.TypeOf((*interface{})(nil)).Elem():  true,
+		reflect.TypeOf(json.RawMessage{}):           true,
+		reflect.TypeOf(&json.RawMessage{}):          true,
+		reflect.TypeOf([]interface{}{}):             true,
+		reflect.TypeOf(map[string]interface{}{}):    true,
+		reflect.TypeOf((*fmt.Stringer)(nil)).Elem(): false,
+		reflect.TypeOf([]byte{}):                    false,
+		reflect.TypeOf(Struct{}):                    false,
+		reflect.TypeOf(map[string]string{}):         false,
+	}
+
+	for typ, expected := range testCases {
+		if IsFreeFormJSON(typ) != expected {
+			t.Fatalf("Expected %t for %s", expected, typ)
+		}
+	}
+}
+
+func TestConversionForType(t *testing.T) {
+	type Struct struct{}
+	testCases := map[reflect.Type]Conversion{
+		reflect.TypeOf(json.RawMessage{}): JSONConversion,
+		reflect.TypeOf([]byte{}):          Base64Conversion,
+		reflect.TypeOf(time.Duration(0)):  DurationConversion,
+		reflect.TypeOf(time.Time{}):       TimeConversion,
+		reflect.TypeOf(&time.Time{}):      TimeConversion,
+		reflect.TypeOf([]int{}):           NoConversion,
+		reflect.TypeOf(int64(0)):          NoConversion,
+		reflect.TypeOf(Struct{}):          NoConversion,
+	}
+
+	for typ, expected := range testCases {
+		conv := ConversionForType(typ)
+		if conv != expected {
+			t.Fatalf("Expected %q for %s, given: %q", expected, typ, conv)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package util
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// WalkResource calls fn for each field of the resource depth-first
+// (sorted by name), descending into nested blocks, e.g. [metadata name]
+func WalkResource(res *schema.Resource, fn func(path []string, s *schema.Schema)) {
+	walkSchemaMap(res.Schema, nil, fn)
+}
+
+func walkSchemaMap(m map[string]*schema.Schema, parent []string, fn func(path []string, s *schema.Schema)) {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := m[name]
+		path := append(append([]string{}, parent...), name)
+		fn(path, s)
+		if v, isResource := s.Elem.(*schema.Resource); isResource {
+			walkSchemaMap(v.Schema, path, fn)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// CRD is a Kubernetes CustomResourceDefinition
+// (apiextensions.k8s.io/v1 or v1beta1)
+type CRD struct {
+	Group  string
+	Kind   string
+	Plural string
+
+	// openAPIV3Schema of each version
+	Versions map[string]*Schema
+}
+
+type crdManifest struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Group string `json:"group"`
+		Names struct {
+			Kind   string `json:"kind"`
+			Plural string `json:"plural"`
+		} `json:"names"`
+
+		// v1beta1 only
+		Version    string           `json:"version"`
+		Validation *crdValidation   `json:"validation"`
+		Versions   []crdVersionSpec `json:"versions"`
+	} `json:"spec"`
+}
+
+type crdVersionSpec struct {
+	Name   string         `json:"name"`
+	Schema *crdValidation `json:"schema"`
+}
+
+type crdValidation struct {
+	OpenAPIV3Schema *Schema `json:"openAPIV3Schema"`
+}
+
+func LoadCRDFile(path string) (*CRD, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCRD(b)
+}
+
+// ParseCRD decodes a CRD manifest in either YAML or JSON
+func ParseCRD(b []byte) (*CRD, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		var err error
+		b, err = yamlToJSON(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m := &crdManifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	if m.Kind != "CustomResourceDefinition" {
+		return nil, fmt.Errorf("Expected CustomResourceDefinition, given %q", m.Kind)
+	}
+
+	crd := &CRD{
+		Group:    m.Spec.Group,
+		Kind:     m.Spec.Names.Kind,
+		Plural:   m.Spec.Names.Plural,
+		Versions: make(map[string]*Schema, 0),
+	}
+
+	// Schema shared by all versions (v1beta1) can be overridden per version
+	var shared *Schema
+	if m.Spec.Validation != nil {
+		shared = m.Spec.Validation.OpenAPIV3Schema
+	}
+	if m.Spec.Version != "" && shared != nil {
+		crd.Versions[m.Spec.Version] = shared
+	}
+	for _, v := range m.Spec.Versions {
+		schema := shared
+		if v.Schema != nil && v.Schema.OpenAPIV3Schema != nil {
+			schema = v.Schema.OpenAPIV3Schema
+		}
+		if schema != nil {
+			crd.Versions[v.Name] = schema
+		}
+	}
+
+	return crd, nil
+}
+
+// Schema returns openAPIV3Schema of the given version,
+// its fields are available via Generator.FieldsFromSchema
+func (c *CRD) Schema(version string) (*Schema, error) {
+	schema, ok := c.Versions[version]
+	if !ok {
+		return nil, fmt.Errorf("No schema found for %s/%s of %s", c.Group, version, c.Kind)
+	}
+	return schema, nil
+}
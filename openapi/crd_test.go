@@ -0,0 +1,135 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+const cronTabCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  names:
+    kind: CronTab
+    plural: crontabs
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            required: [cronSpec, image]
+            properties:
+              cronSpec:
+                type: string
+                description: Schedule in Cron format.
+              image:
+                type: string
+              replicas:
+                type: integer
+                default: 1
+              ratio:
+                type: number
+                default: 2
+              policy:
+                type: string
+                enum: [Allow, Forbid]
+                default: Allow
+              port:
+                x-kubernetes-int-or-string: true
+              args:
+                type: array
+                x-kubernetes-list-type: atomic
+                items:
+                  type: string
+              template:
+                type: object
+                x-kubernetes-preserve-unknown-fields: true
+              settings:
+                type: object
+                x-kubernetes-preserve-unknown-fields: true
+                properties:
+                  verbose:
+                    type: boolean
+              sidecar:
+                type: object
+                x-kubernetes-embedded-resource: true
+                properties:
+                  apiVersion:
+                    type: string
+                  kind:
+                    type: string
+              target:
+                oneOf:
+                - required: [url]
+                - required: [service]
+                properties:
+                  url:
+                    type: string
+                  service:
+                    type: string
+              timeout:
+                anyOf:
+                - type: integer
+                - type: string
+`
+
+func TestParseCRD(t *testing.T) {
+	crd, err := ParseCRD([]byte(cronTabCRD))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crd.Group != "stable.example.com" || crd.Kind != "CronTab" || crd.Plural != "crontabs" {
+		t.Fatalf("Unexpected CRD: %#v", crd)
+	}
+	if _, err := crd.Schema("v2"); err == nil {
+		t.Fatal("Expected error for unknown version")
+	}
+
+	s, err := crd.Schema("v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &Generator{}
+	fields, err := g.FieldsFromSchema(s.Properties["spec"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := schemagen.RenderFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"args":      "{\nType: schema.TypeList,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\n}",
+		"cron_spec": "{\nType: schema.TypeString,\nDescription: \"Schedule in Cron format.\",\nRequired: true,\n}",
+		"image":     "{\nType: schema.TypeString,\nRequired: true,\n}",
+		"policy":    "{\nType: schema.TypeString,\nDescription: \"Possible values: `Allow`, `Forbid`. Defaults to `Allow`.\",\nOptional: true,\nDefault: \"Allow\",\nValidateFunc: validation.StringInSlice([]string{\"Allow\", \"Forbid\"}, false),\n}",
+		"port":      "{\nType: schema.TypeString,\nOptional: true,\n}",
+		"ratio":     "{\nType: schema.TypeFloat,\nDescription: \"Defaults to `2`.\",\nOptional: true,\nDefault: 2.0,\n}",
+		"replicas":  "{\nType: schema.TypeInt,\nDescription: \"Defaults to `1`.\",\nOptional: true,\nDefault: 1,\n}",
+		"settings":  "{\nType: schema.TypeString,\nOptional: true,\nStateFunc: normalizeJSONString,\nDiffSuppressFunc: suppressEquivalentJSONDiffs,\n}",
+		"sidecar":   "{\nType: schema.TypeString,\nOptional: true,\nStateFunc: normalizeJSONString,\nDiffSuppressFunc: suppressEquivalentJSONDiffs,\n}",
+		"target":    "{\nType: schema.TypeList,\nOptional: true,\nMaxItems: 1,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"service\": {\nType: schema.TypeString,\nOptional: true,\n},\n\"url\": {\nType: schema.TypeString,\nOptional: true,\n},\n},\n},\n}",
+		"template":  "{\nType: schema.TypeString,\nOptional: true,\nStateFunc: normalizeJSONString,\nDiffSuppressFunc: suppressEquivalentJSONDiffs,\n}",
+		"timeout":   "{\nType: schema.TypeString,\nOptional: true,\n}",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+}
+
+func TestParseCRD_notCRD(t *testing.T) {
+	_, err := ParseCRD([]byte("apiVersion: v1\nkind: Pod\n"))
+	if err == nil {
+		t.Fatal("Expected error for a manifest other than CRD")
+	}
+}
@@ -0,0 +1,387 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+// Generator builds the same field model as schemagen.SchemaGenerator,
+// but from definitions of an OpenAPI document instead of Go structs.
+// Enums are validated via the SDK's helper/validation package.
+type Generator struct {
+	schemagen.HelperFuncSet
+
+	Spec *Spec
+
+	refStack []string
+}
+
+// FieldsFromRoot builds the schema tree of a JSON Schema document (see ParseJSONSchema)
+func (g *Generator) FieldsFromRoot() ([]*schemagen.Field, error) {
+	if g.Spec == nil || g.Spec.Root == nil {
+		return nil, fmt.Errorf("No root schema found")
+	}
+
+	g.refStack = append(g.refStack, "#")
+	defer func() {
+		g.refStack = g.refStack[:len(g.refStack)-1]
+	}()
+
+	return g.FieldsFromSchema(g.Spec.Root)
+}
+
+// FieldsFromDefinition builds the schema tree (sorted by field name)
+// which may be rendered via schemagen.RenderFields
+func (g *Generator) FieldsFromDefinition(name string) ([]*schemagen.Field, error) {
+	def, err := g.Spec.Definition(name)
+	if err != nil {
+		return nil, err
+	}
+
+	g.refStack = append(g.refStack, name)
+	defer func() {
+		g.refStack = g.refStack[:len(g.refStack)-1]
+	}()
+
+	return g.FieldsFromSchema(def)
+}
+
+func (g *Generator) FieldsFromSchema(s *Schema) ([]*schemagen.Field, error) {
+	properties, required, err := g.properties(s)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]*schemagen.Field, 0, len(properties))
+	for name, prop := range properties {
+		f, err := g.generateField(name, prop)
+		if err != nil {
+			log.Printf("ERROR: %s", err)
+			continue
+		}
+		f.Name = fieldName(name)
+
+		// Defaulted fields never need to be set
+		switch {
+		case f.Schema.Computed:
+		case required[name] && f.Schema.Default == nil:
+			f.Schema.Required = true
+		default:
+			f.Schema.Optional = true
+		}
+		fields = append(fields, f)
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+	return fields, nil
+}
+
+// Properties of allOf members are merged into the parent,
+// as well as those of oneOf/anyOf alternatives, but never as required
+func (g *Generator) properties(s *Schema) (map[string]*Schema, map[string]bool, error) {
+	s, pop, err := g.resolve(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer pop()
+
+	properties := make(map[string]*Schema, len(s.Properties))
+	required := make(map[string]bool, len(s.Required))
+	for _, of := range s.AllOf {
+		p, r, err := g.properties(of)
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, v := range p {
+			properties[k] = v
+		}
+		for k := range r {
+			required[k] = true
+		}
+	}
+	for _, alt := range s.alternatives() {
+		p, _, err := g.properties(alt)
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, v := range p {
+			properties[k] = v
+		}
+	}
+	for k, v := range s.Properties {
+		properties[k] = v
+	}
+	for _, k := range s.Required {
+		required[k] = true
+	}
+	return properties, required, nil
+}
+
+// Mode of the field (Required/Optional) is up to the parent
+func (g *Generator) generateField(name string, prop *Schema) (*schemagen.Field, error) {
+	prop, pop, err := g.resolve(prop)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to process %q: %s", name, err)
+	}
+	defer pop()
+
+	s := &schema.Schema{
+		Description: prop.Description,
+		Computed:    prop.ReadOnly,
+	}
+	f := &schemagen.Field{Schema: s, Funcs: &schemagen.SchemaFuncs{}}
+
+	switch g.schemaType(prop) {
+	case "integer":
+		s.Type = schema.TypeInt
+	case "number":
+		s.Type = schema.TypeFloat
+	case "boolean":
+		s.Type = schema.TypeBool
+	case "string":
+		s.Type = schema.TypeString
+		if prop.Format == "date-time" {
+			f.Funcs.ValidateFunc = g.DeclareHelperFunc("validateRFC3339Time")
+		}
+	case "array":
+		if prop.Items == nil {
+			return nil, fmt.Errorf("Unable to process %q: array without items", name)
+		}
+		// TODO: TypeList may be more suitable for some situations
+		s.Type = schema.TypeSet
+		if prop.ListType == "atomic" || prop.ListType == "map" {
+			s.Type = schema.TypeList
+		}
+		if g.isObject(prop.Items) {
+			block, err := g.generateBlock(name, prop.Items)
+			if err != nil {
+				return nil, err
+			}
+			f.Block = block
+			break
+		}
+
+		elem, err := g.generateField(name, prop.Items)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to generate Elem for %q: %s", name, err)
+		}
+		elem.Schema.Computed = false
+		elem.Schema.Description = ""
+		f.Elem = elem
+		if s.Type == schema.TypeSet && elem.Schema.Type == schema.TypeString {
+			f.Funcs.Set = "schema.HashString"
+		}
+	case "object", "":
+		if g.isObject(prop) {
+			block, err := g.generateBlock(name, prop)
+			if err != nil {
+				return nil, err
+			}
+			s.Type = schema.TypeList
+			s.MaxItems = 1
+			f.Block = block
+			break
+		}
+		if prop.hasAdditionalProperties() && !prop.isFreeForm() {
+			s.Type = schema.TypeMap
+			break
+		}
+
+		// Free-form object, also one with properties if unknown fields are preserved
+		s.Type = schema.TypeString
+		f.Funcs.StateFunc = g.DeclareHelperFunc("normalizeJSONString")
+		f.Funcs.DiffSuppressFunc = g.DeclareHelperFunc("suppressEquivalentJSONDiffs")
+	default:
+		return nil, fmt.Errorf("Unable to process %q: unknown type %q", name, prop.Type)
+	}
+
+	if len(prop.Enum) > 0 {
+		validateFunc, values, err := enumValidateFunc(s.Type, prop.Enum)
+		if err != nil {
+			log.Printf("WARN: %q: %s - SKIPPING validation", name, err)
+		} else {
+			f.Funcs.ValidateFunc = validateFunc
+			schemagen.AppendDescription(s, "Possible values: "+values+".")
+		}
+	}
+
+	// Computed fields can't have defaults
+	if prop.Default != nil && !s.Computed {
+		d, err := defaultValue(s.Type, prop.Default)
+		if err != nil {
+			log.Printf("Ignoring default of %q: %s", name, err)
+		} else {
+			s.Default = d
+			schemagen.AppendDescription(s, fmt.Sprintf("Defaults to `%v`.", d))
+		}
+	}
+
+	return f, nil
+}
+
+func (g *Generator) generateBlock(name string, s *Schema) (*schemagen.Block, error) {
+	fields, err := g.FieldsFromSchema(s)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to process %q: %s", name, err)
+	}
+	return &schemagen.Block{Fields: fields}, nil
+}
+
+func (g *Generator) isObject(s *Schema) bool {
+	s, pop, err := g.resolve(s)
+	if err != nil {
+		return false
+	}
+	defer pop()
+
+	if s.isFreeForm() {
+		return false
+	}
+	if len(s.Properties) > 0 || len(s.AllOf) > 0 {
+		return true
+	}
+	for _, alt := range s.alternatives() {
+		if g.isObject(alt) {
+			return true
+		}
+	}
+	return false
+}
+
+// Type may also be implied by Kubernetes extensions or oneOf/anyOf alternatives,
+// alternatives of different primitive types are represented as string
+func (g *Generator) schemaType(s *Schema) SchemaType {
+	if s.IntOrString {
+		return "string"
+	}
+	if s.Type != "" {
+		return s.Type
+	}
+
+	var typ SchemaType
+	for _, alt := range s.alternatives() {
+		alt, pop, err := g.resolve(alt)
+		if err != nil {
+			continue
+		}
+		altType := g.schemaType(alt)
+		pop()
+
+		switch {
+		case altType == "" || altType == "object" || altType == "array":
+			return ""
+		case typ != "" && typ != altType:
+			typ = "string"
+		default:
+			typ = altType
+		}
+	}
+	return typ
+}
+
+// Follows $ref (if any), the returned func pops it from the stack
+func (g *Generator) resolve(s *Schema) (*Schema, func(), error) {
+	if s.Ref == "" {
+		return s, func() {}, nil
+	}
+
+	if g.Spec == nil {
+		return nil, nil, fmt.Errorf("Unable to resolve %q without Spec", s.Ref)
+	}
+	name, resolved, err := g.Spec.resolve(s.Ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ref := range g.refStack {
+		if ref == name {
+			return nil, nil, fmt.Errorf("Cycle detected: %s -> %s",
+				strings.Join(g.refStack, " -> "), name)
+		}
+	}
+
+	g.refStack = append(g.refStack, name)
+	return resolved, func() {
+		g.refStack = g.refStack[:len(g.refStack)-1]
+	}, nil
+}
+
+// null members of nullable enums are left out
+func enumValidateFunc(t schema.ValueType, enum []interface{}) (string, string, error) {
+	nonNull := make([]interface{}, 0, len(enum))
+	for _, v := range enum {
+		if v != nil {
+			nonNull = append(nonNull, v)
+		}
+	}
+	if len(nonNull) == 0 {
+		return "", "", fmt.Errorf("Enum has no values")
+	}
+	enum = nonNull
+
+	values := make([]string, len(enum))
+	docs := make([]string, len(enum))
+	for i, v := range enum {
+		switch t {
+		case schema.TypeString:
+			s, ok := v.(string)
+			if !ok {
+				return "", "", fmt.Errorf("Unexpected enum value %#v", v)
+			}
+			values[i] = fmt.Sprintf("%q", s)
+		case schema.TypeInt:
+			n, ok := v.(float64)
+			if !ok {
+				return "", "", fmt.Errorf("Unexpected enum value %#v", v)
+			}
+			values[i] = fmt.Sprintf("%d", int(n))
+		default:
+			return "", "", fmt.Errorf("Enum of %s is not supported", t)
+		}
+		docs[i] = fmt.Sprintf("`%v`", v)
+	}
+
+	list := strings.Join(values, ", ")
+	if t == schema.TypeInt {
+		return fmt.Sprintf("validation.IntInSlice([]int{%s})", list), strings.Join(docs, ", "), nil
+	}
+	return fmt.Sprintf("validation.StringInSlice([]string{%s}, false)", list), strings.Join(docs, ", "), nil
+}
+
+func defaultValue(t schema.ValueType, v interface{}) (interface{}, error) {
+	switch t {
+	case schema.TypeInt:
+		if n, ok := v.(float64); ok {
+			return int(n), nil
+		}
+	case schema.TypeFloat:
+		if n, ok := v.(float64); ok {
+			return n, nil
+		}
+	case schema.TypeBool:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	case schema.TypeString:
+		switch d := v.(type) {
+		case string:
+			return d, nil
+		case float64:
+			// e.g. x-kubernetes-int-or-string
+			return fmt.Sprintf("%v", d), nil
+		}
+	}
+	return nil, fmt.Errorf("Unsupported default %#v for %s", v, t)
+}
+
+// Property names are typically camelCase, sometimes with dashes
+func fieldName(property string) string {
+	return u.Underscore(strings.Replace(property, "-", "_", -1))
+}
@@ -0,0 +1,228 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+const petStoreSpec = `openapi: 3.0.0
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        id:
+          type: integer
+          readOnly: true
+        name:
+          type: string
+          description: Name of the pet
+        status:
+          type: string
+          description: Status in the store.
+          enum: [available, sold]
+        tags:
+          type: array
+          items:
+            type: string
+        bornAt:
+          type: string
+          format: date-time
+        labels:
+          type: object
+          additionalProperties:
+            type: string
+        extra:
+          type: object
+        owner:
+          $ref: '#/components/schemas/Owner'
+        toys:
+          type: array
+          items:
+            $ref: '#/components/schemas/Toy'
+    Owner:
+      type: object
+      properties:
+        firstName:
+          type: string
+    Toy:
+      allOf:
+      - $ref: '#/components/schemas/Named'
+      - properties:
+          size:
+            type: integer
+            enum: [1, 2]
+    Named:
+      required: [name]
+      properties:
+        name:
+          type: string
+`
+
+func TestFieldsFromDefinition_v3(t *testing.T) {
+	spec, err := Parse([]byte(petStoreSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Generator{Spec: spec}
+	fields, err := g.FieldsFromDefinition("Pet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := schemagen.RenderFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"born_at": "{\nType: schema.TypeString,\nOptional: true,\nValidateFunc: validateRFC3339Time,\n}",
+		"extra":   "{\nType: schema.TypeString,\nOptional: true,\nStateFunc: normalizeJSONString,\nDiffSuppressFunc: suppressEquivalentJSONDiffs,\n}",
+		"id":      "{\nType: schema.TypeInt,\nComputed: true,\n}",
+		"labels":  "{\nType: schema.TypeMap,\nOptional: true,\n}",
+		"name":    "{\nType: schema.TypeString,\nDescription: \"Name of the pet\",\nRequired: true,\n}",
+		"owner":   "{\nType: schema.TypeList,\nOptional: true,\nMaxItems: 1,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"first_name\": {\nType: schema.TypeString,\nOptional: true,\n},\n},\n},\n}",
+		"status":  "{\nType: schema.TypeString,\nDescription: \"Status in the store. Possible values: `available`, `sold`.\",\nOptional: true,\nValidateFunc: validation.StringInSlice([]string{\"available\", \"sold\"}, false),\n}",
+		"tags":    "{\nType: schema.TypeSet,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\nSet: schema.HashString,\n}",
+		"toys":    "{\nType: schema.TypeSet,\nOptional: true,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"name\": {\nType: schema.TypeString,\nRequired: true,\n},\n\"size\": {\nType: schema.TypeInt,\nDescription: \"Possible values: `1`, `2`.\",\nOptional: true,\nValidateFunc: validation.IntInSlice([]int{1, 2}),\n},\n},\n},\n}",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+
+	funcs := g.SchemaFunctions()
+	expectedFuncs := map[string]string{
+		"normalizeJSONString":         schemagen.HelperFuncs["normalizeJSONString"],
+		"suppressEquivalentJSONDiffs": schemagen.HelperFuncs["suppressEquivalentJSONDiffs"],
+		"validateRFC3339Time":         schemagen.HelperFuncs["validateRFC3339Time"],
+	}
+	if !reflect.DeepEqual(funcs, expectedFuncs) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFuncs, funcs)
+	}
+}
+
+const treeSpec = `{
+  "swagger": "2.0",
+  "definitions": {
+    "Node": {
+      "required": ["value"],
+      "properties": {
+        "value": {"type": "number"},
+        "children": {
+          "type": "array",
+          "items": {"$ref": "#/definitions/Node"}
+        }
+      }
+    }
+  }
+}`
+
+func TestFieldsFromDefinition_v2cycle(t *testing.T) {
+	spec, err := Parse([]byte(treeSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Generator{Spec: spec}
+	fields, err := g.FieldsFromDefinition("Node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := schemagen.RenderFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"value": "{\nType: schema.TypeFloat,\nRequired: true,\n}",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+}
+
+const enumSpec = `{
+  "swagger": "2.0",
+  "definitions": {
+    "Pet": {
+      "properties": {
+        "status": {"type": "string", "enum": ["available", "sold", null]},
+        "vaccinated": {"type": "boolean", "enum": [true]}
+      }
+    }
+  }
+}`
+
+func TestFieldsFromDefinition_enums(t *testing.T) {
+	spec, err := Parse([]byte(enumSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Generator{Spec: spec}
+	fields, err := g.FieldsFromDefinition("Pet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := schemagen.RenderFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"status":     "{\nType: schema.TypeString,\nDescription: \"Possible values: `available`, `sold`.\",\nOptional: true,\nValidateFunc: validation.StringInSlice([]string{\"available\", \"sold\"}, false),\n}",
+		"vaccinated": "{\nType: schema.TypeBool,\nOptional: true,\n}",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+}
+
+func TestParse_noVersion(t *testing.T) {
+	_, err := Parse([]byte(`{"definitions": {}}`))
+	if err == nil {
+		t.Fatal("Expected error for document without version")
+	}
+}
+
+const personJSONSchema = `$schema: https://json-schema.org/draft/2020-12/schema
+type: object
+required: [name]
+properties:
+  name:
+    type: [string, "null"]
+  address:
+    $ref: '#/$defs/Address'
+  parent:
+    $ref: '#'
+$defs:
+  Address:
+    type: object
+    properties:
+      city:
+        type: string
+`
+
+func TestFieldsFromRoot_jsonSchema(t *testing.T) {
+	spec, err := ParseJSONSchema([]byte(personJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Generator{Spec: spec}
+	fields, err := g.FieldsFromRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := schemagen.RenderFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"address": "{\nType: schema.TypeList,\nOptional: true,\nMaxItems: 1,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"city\": {\nType: schema.TypeString,\nOptional: true,\n},\n},\n},\n}",
+		"name":    "{\nType: schema.TypeString,\nRequired: true,\n}",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+}
@@ -0,0 +1,242 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+// HelpersFromSchema generates expander & flattener (keyed by function name)
+// converting between fields built by FieldsFromSchema and the unstructured object
+// the schema describes, e.g. content of unstructured.Unstructured in case of CRDs.
+// name is used in function names (e.g. CronTabSpec -> expandCronTabSpec),
+// nested objects get their own functions named after the property.
+// Generated code refers to encoding/json, fmt, strconv and helper/schema.
+func (g *Generator) HelpersFromSchema(name string, s *Schema) (map[string]string, error) {
+	helpers := make(map[string]string, 0)
+	if err := g.generateHelpers(name, s, helpers); err != nil {
+		return nil, err
+	}
+	return helpers, nil
+}
+
+func (g *Generator) generateHelpers(name string, s *Schema, helpers map[string]string) error {
+	properties, required, err := g.properties(s)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(properties))
+	for p := range properties {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+
+	expandBody, flattenBody := "", ""
+	for _, p := range names {
+		// Same field as built by FieldsFromSchema
+		f, err := g.generateField(p, properties[p])
+		if err != nil {
+			log.Printf("ERROR: %s", err)
+			continue
+		}
+		omitEmpty := !required[p] && f.Schema.Default == nil
+		e, fl, err := g.propertyHelpers(name, p, properties[p], f, omitEmpty, helpers)
+		if err != nil {
+			log.Printf("WARN: %q: %s - SKIPPING", p, err)
+			e = fmt.Sprintf("// TODO: %s: %s\n", p, err)
+			fl = e
+		}
+		// Read-only fields are never sent
+		if !f.Schema.Computed {
+			expandBody += e
+		}
+		flattenBody += fl
+	}
+
+	helpers["expand"+name] = fmt.Sprintf(`func expand%s(l []interface{}) map[string]interface{} {
+obj := make(map[string]interface{})
+if len(l) == 0 || l[0] == nil {
+return obj
+}
+in := l[0].(map[string]interface{})
+%sreturn obj
+}`, name, expandBody)
+	helpers["flatten"+name] = fmt.Sprintf(`func flatten%s(in map[string]interface{}) []interface{} {
+att := make(map[string]interface{})
+%sreturn []interface{}{att}
+}`, name, flattenBody)
+	return nil
+}
+
+// Returns code expanding & flattening a single property
+func (g *Generator) propertyHelpers(funcName, name string, prop *Schema, f *schemagen.Field,
+	omitEmpty bool, helpers map[string]string) (string, string, error) {
+	prop, pop, err := g.resolve(prop)
+	if err != nil {
+		return "", "", err
+	}
+	defer pop()
+
+	key := fieldName(name)
+	s := f.Schema
+
+	if f.Block != nil {
+		nestedName := funcName + u.Camelize(key)
+		if s.Type == schema.TypeList && s.MaxItems == 1 {
+			if err := g.generateHelpers(nestedName, prop, helpers); err != nil {
+				return "", "", err
+			}
+			return fmt.Sprintf(`if v, ok := in[%q].([]interface{}); ok && len(v) > 0 {
+obj[%q] = expand%s(v)
+}
+`, key, name, nestedName), fmt.Sprintf(`if v, ok := in[%q].(map[string]interface{}); ok {
+att[%q] = flatten%s(v)
+}
+`, name, key, nestedName), nil
+		}
+
+		items, popItems, err := g.resolve(prop.Items)
+		if err != nil {
+			return "", "", err
+		}
+		defer popItems()
+		if err := g.generateHelpers(nestedName, items, helpers); err != nil {
+			return "", "", err
+		}
+		return fmt.Sprintf(`if v, ok := in[%q]; ok {
+l := %s
+if len(l) > 0 {
+s := make([]interface{}, len(l))
+for i, n := range l {
+s[i] = expand%s([]interface{}{n})
+}
+obj[%q] = s
+}
+}
+`, key, listValue(s.Type, "v"), nestedName, name), fmt.Sprintf(`if v, ok := in[%q].([]interface{}); ok {
+s := make([]interface{}, 0, len(v))
+for _, n := range v {
+if m, ok := n.(map[string]interface{}); ok {
+s = append(s, flatten%s(m)[0])
+}
+}
+att[%q] = s
+}
+`, name, nestedName, key), nil
+	}
+
+	switch s.Type {
+	case schema.TypeSet, schema.TypeList:
+		elem := f.Elem
+		items, popItems, err := g.resolve(prop.Items)
+		if err != nil {
+			return "", "", err
+		}
+		defer popItems()
+		if elem.Schema.Type == schema.TypeString && items.Type != "string" {
+			return "", "", fmt.Errorf("Arrays of values stored as string are not supported")
+		}
+		value := "l"
+		if elem.Schema.Type == schema.TypeInt {
+			// Unstructured objects hold integers as int64
+			value = "sliceOfInt64(l)"
+			helpers["sliceOfInt64"] = sliceOfInt64Func
+		}
+		return fmt.Sprintf(`if v, ok := in[%q]; ok {
+l := %s
+if len(l) > 0 {
+obj[%q] = %s
+}
+}
+`, key, listValue(s.Type, "v"), name, value), fmt.Sprintf(`if v, ok := in[%q].([]interface{}); ok {
+att[%q] = v
+}
+`, name, key), nil
+	case schema.TypeMap:
+		return fmt.Sprintf(`if v, ok := in[%q].(map[string]interface{}); ok && len(v) > 0 {
+obj[%q] = v
+}
+`, key, name), fmt.Sprintf(`if v, ok := in[%q].(map[string]interface{}); ok {
+att[%q] = v
+}
+`, name, key), nil
+	case schema.TypeString:
+		// Free-form objects are JSON-encoded, invalid JSON is left out
+		if f.Funcs.StateFunc != "" {
+			return fmt.Sprintf(`if v, ok := in[%q].(string); ok && v != "" {
+var o interface{}
+if err := json.Unmarshal([]byte(v), &o); err == nil {
+obj[%q] = o
+}
+}
+`, key, name), fmt.Sprintf(`if v, ok := in[%q]; ok && v != nil {
+b, _ := json.Marshal(v)
+att[%q] = string(b)
+}
+`, name, key), nil
+		}
+		// Values of other types (e.g. x-kubernetes-int-or-string) are stored as string,
+		// numbers are sent as integers
+		if prop.Type != "string" {
+			return fmt.Sprintf(`if v, ok := in[%q].(string); ok && v != "" {
+if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+obj[%q] = n
+} else {
+obj[%q] = v
+}
+}
+`, key, name, name), fmt.Sprintf(`if v, ok := in[%q]; ok && v != nil {
+att[%q] = fmt.Sprintf("%%v", v)
+}
+`, name, key), nil
+		}
+		return primitiveHelpers(key, name, "string", `v != ""`, "v", omitEmpty), primitiveFlattener(key, name), nil
+	case schema.TypeInt:
+		// Unstructured objects hold integers as int64
+		return primitiveHelpers(key, name, "int", "v != 0", "int64(v)", omitEmpty), primitiveFlattener(key, name), nil
+	case schema.TypeFloat:
+		return primitiveHelpers(key, name, "float64", "v != 0", "v", omitEmpty), primitiveFlattener(key, name), nil
+	case schema.TypeBool:
+		return primitiveHelpers(key, name, "bool", "v", "v", omitEmpty), primitiveFlattener(key, name), nil
+	}
+
+	return "", "", fmt.Errorf("Unsupported type %s", s.Type)
+}
+
+// Empty values of optional fields (without default) are left out
+func primitiveHelpers(key, name, goType, notEmpty, value string, omitEmpty bool) string {
+	condition := "ok"
+	if omitEmpty {
+		condition += " && " + notEmpty
+	}
+	return fmt.Sprintf(`if v, ok := in[%q].(%s); %s {
+obj[%q] = %s
+}
+`, key, goType, condition, name, value)
+}
+
+func primitiveFlattener(key, name string) string {
+	return fmt.Sprintf(`if v, ok := in[%q]; ok && v != nil {
+att[%q] = v
+}
+`, name, key)
+}
+
+func listValue(t schema.ValueType, v string) string {
+	if t == schema.TypeSet {
+		return fmt.Sprintf("%s.(*schema.Set).List()", v)
+	}
+	return fmt.Sprintf("%s.([]interface{})", v)
+}
+
+const sliceOfInt64Func = `func sliceOfInt64(in []interface{}) []interface{} {
+out := make([]interface{}, len(in))
+for i, v := range in {
+out[i] = int64(v.(int))
+}
+return out
+}`
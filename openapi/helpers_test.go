@@ -0,0 +1,191 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+const containerJSONSchema = `type: object
+required: [image]
+properties:
+  image:
+    type: string
+  replicas:
+    type: integer
+  port:
+    x-kubernetes-int-or-string: true
+  ports:
+    type: array
+    items:
+      type: integer
+  labels:
+    type: object
+    additionalProperties:
+      type: string
+  template:
+    type: object
+    x-kubernetes-preserve-unknown-fields: true
+  status:
+    type: string
+    readOnly: true
+  volume:
+    type: object
+    properties:
+      name:
+        type: string
+  env:
+    type: array
+    items:
+      type: object
+      properties:
+        name:
+          type: string
+`
+
+func TestHelpersFromSchema(t *testing.T) {
+	spec, err := ParseJSONSchema([]byte(containerJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Generator{Spec: spec}
+	output, err := g.HelpersFromSchema("Container", spec.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"expandContainer": `func expandContainer(l []interface{}) map[string]interface{} {
+obj := make(map[string]interface{})
+if len(l) == 0 || l[0] == nil {
+return obj
+}
+in := l[0].(map[string]interface{})
+if v, ok := in["env"]; ok {
+l := v.(*schema.Set).List()
+if len(l) > 0 {
+s := make([]interface{}, len(l))
+for i, n := range l {
+s[i] = expandContainerEnv([]interface{}{n})
+}
+obj["env"] = s
+}
+}
+if v, ok := in["image"].(string); ok {
+obj["image"] = v
+}
+if v, ok := in["labels"].(map[string]interface{}); ok && len(v) > 0 {
+obj["labels"] = v
+}
+if v, ok := in["port"].(string); ok && v != "" {
+if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+obj["port"] = n
+} else {
+obj["port"] = v
+}
+}
+if v, ok := in["ports"]; ok {
+l := v.(*schema.Set).List()
+if len(l) > 0 {
+obj["ports"] = sliceOfInt64(l)
+}
+}
+if v, ok := in["replicas"].(int); ok && v != 0 {
+obj["replicas"] = int64(v)
+}
+if v, ok := in["template"].(string); ok && v != "" {
+var o interface{}
+if err := json.Unmarshal([]byte(v), &o); err == nil {
+obj["template"] = o
+}
+}
+if v, ok := in["volume"].([]interface{}); ok && len(v) > 0 {
+obj["volume"] = expandContainerVolume(v)
+}
+return obj
+}`,
+		"expandContainerEnv": `func expandContainerEnv(l []interface{}) map[string]interface{} {
+obj := make(map[string]interface{})
+if len(l) == 0 || l[0] == nil {
+return obj
+}
+in := l[0].(map[string]interface{})
+if v, ok := in["name"].(string); ok && v != "" {
+obj["name"] = v
+}
+return obj
+}`,
+		"expandContainerVolume": `func expandContainerVolume(l []interface{}) map[string]interface{} {
+obj := make(map[string]interface{})
+if len(l) == 0 || l[0] == nil {
+return obj
+}
+in := l[0].(map[string]interface{})
+if v, ok := in["name"].(string); ok && v != "" {
+obj["name"] = v
+}
+return obj
+}`,
+		"flattenContainer": `func flattenContainer(in map[string]interface{}) []interface{} {
+att := make(map[string]interface{})
+if v, ok := in["env"].([]interface{}); ok {
+s := make([]interface{}, 0, len(v))
+for _, n := range v {
+if m, ok := n.(map[string]interface{}); ok {
+s = append(s, flattenContainerEnv(m)[0])
+}
+}
+att["env"] = s
+}
+if v, ok := in["image"]; ok && v != nil {
+att["image"] = v
+}
+if v, ok := in["labels"].(map[string]interface{}); ok {
+att["labels"] = v
+}
+if v, ok := in["port"]; ok && v != nil {
+att["port"] = fmt.Sprintf("%v", v)
+}
+if v, ok := in["ports"].([]interface{}); ok {
+att["ports"] = v
+}
+if v, ok := in["replicas"]; ok && v != nil {
+att["replicas"] = v
+}
+if v, ok := in["status"]; ok && v != nil {
+att["status"] = v
+}
+if v, ok := in["template"]; ok && v != nil {
+b, _ := json.Marshal(v)
+att["template"] = string(b)
+}
+if v, ok := in["volume"].(map[string]interface{}); ok {
+att["volume"] = flattenContainerVolume(v)
+}
+return []interface{}{att}
+}`,
+		"flattenContainerEnv": `func flattenContainerEnv(in map[string]interface{}) []interface{} {
+att := make(map[string]interface{})
+if v, ok := in["name"]; ok && v != nil {
+att["name"] = v
+}
+return []interface{}{att}
+}`,
+		"flattenContainerVolume": `func flattenContainerVolume(in map[string]interface{}) []interface{} {
+att := make(map[string]interface{})
+if v, ok := in["name"]; ok && v != nil {
+att["name"] = v
+}
+return []interface{}{att}
+}`,
+		"sliceOfInt64": `func sliceOfInt64(in []interface{}) []interface{} {
+out := make([]interface{}, len(in))
+for i, v := range in {
+out[i] = int64(v.(int))
+}
+return out
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+}
@@ -0,0 +1,193 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is an OpenAPI v3 or Swagger (OpenAPI v2) document,
+// only the parts describing data structures are decoded
+type Spec struct {
+	Swagger     string             `json:"swagger"`
+	OpenAPI     string             `json:"openapi"`
+	Definitions map[string]*Schema `json:"definitions"`
+	Components  struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+
+	// Root schema of a JSON Schema document (referred to as #)
+	Root *Schema `json:"-"`
+}
+
+// Schema is a subset of the Schema Object (a JSON Schema dialect)
+// including Kubernetes extensions used in CRDs
+type Schema struct {
+	Ref         string             `json:"$ref"`
+	Type        SchemaType         `json:"type"`
+	Format      string             `json:"format"`
+	Description string             `json:"description"`
+	Properties  map[string]*Schema `json:"properties"`
+	Required    []string           `json:"required"`
+	Items       *Schema            `json:"items"`
+	AllOf       []*Schema          `json:"allOf"`
+	OneOf       []*Schema          `json:"oneOf"`
+	AnyOf       []*Schema          `json:"anyOf"`
+	Enum        []interface{}      `json:"enum"`
+	Default     interface{}        `json:"default"`
+	ReadOnly    bool               `json:"readOnly"`
+
+	// Either a boolean or a schema of map values
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+
+	// Definitions of a JSON Schema document
+	Definitions map[string]*Schema `json:"definitions"`
+	Defs        map[string]*Schema `json:"$defs"`
+
+	PreserveUnknownFields bool   `json:"x-kubernetes-preserve-unknown-fields"`
+	IntOrString           bool   `json:"x-kubernetes-int-or-string"`
+	EmbeddedResource      bool   `json:"x-kubernetes-embedded-resource"`
+	ListType              string `json:"x-kubernetes-list-type"`
+}
+
+// SchemaType is the JSON Schema type, if it's a list of types
+// (e.g. ["string", "null"]) the first non-null one is used
+type SchemaType string
+
+func (t *SchemaType) UnmarshalJSON(b []byte) error {
+	var types []string
+	if err := json.Unmarshal(b, &types); err != nil {
+		var single string
+		if err := json.Unmarshal(b, &single); err != nil {
+			return err
+		}
+		types = []string{single}
+	}
+
+	*t = ""
+	for _, typ := range types {
+		if typ != "null" {
+			*t = SchemaType(typ)
+			break
+		}
+	}
+	return nil
+}
+
+func LoadFile(path string) (*Spec, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Parse decodes the document in either JSON or YAML
+func Parse(b []byte) (*Spec, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		var err error
+		b, err = yamlToJSON(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	spec := &Spec{}
+	if err := json.Unmarshal(b, spec); err != nil {
+		return nil, err
+	}
+	if spec.Swagger == "" && spec.OpenAPI == "" {
+		return nil, fmt.Errorf("Neither swagger nor openapi version found")
+	}
+	return spec, nil
+}
+
+func yamlToJSON(b []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// ParseJSONSchema decodes a JSON Schema document (in either JSON or YAML),
+// fields of the root schema are available via Generator.FieldsFromRoot
+func ParseJSONSchema(b []byte) (*Spec, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		var err error
+		b, err = yamlToJSON(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	root := &Schema{}
+	if err := json.Unmarshal(b, root); err != nil {
+		return nil, err
+	}
+
+	spec := &Spec{
+		Definitions: make(map[string]*Schema, len(root.Definitions)+len(root.Defs)),
+		Root:        root,
+	}
+	for name, def := range root.Definitions {
+		spec.Definitions[name] = def
+	}
+	for name, def := range root.Defs {
+		spec.Definitions[name] = def
+	}
+	return spec, nil
+}
+
+func LoadJSONSchemaFile(path string) (*Spec, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJSONSchema(b)
+}
+
+// Definition looks up a named schema, e.g. io.k8s.api.core.v1.Pod
+func (s *Spec) Definition(name string) (*Schema, error) {
+	if schema, ok := s.Definitions[name]; ok {
+		return schema, nil
+	}
+	if schema, ok := s.Components.Schemas[name]; ok {
+		return schema, nil
+	}
+	return nil, fmt.Errorf("Definition %q not found", name)
+}
+
+// Only local references are supported, e.g. #/definitions/Pet or #/components/schemas/Pet
+func (s *Spec) resolve(ref string) (string, *Schema, error) {
+	if ref == "#" && s.Root != nil {
+		return ref, s.Root, nil
+	}
+	for _, prefix := range []string{"#/definitions/", "#/$defs/", "#/components/schemas/"} {
+		if strings.HasPrefix(ref, prefix) {
+			name := strings.TrimPrefix(ref, prefix)
+			schema, err := s.Definition(name)
+			return name, schema, err
+		}
+	}
+	return "", nil, fmt.Errorf("Unsupported reference %q", ref)
+}
+
+func (s *Schema) hasAdditionalProperties() bool {
+	raw := string(bytes.TrimSpace(s.AdditionalProperties))
+	return raw != "" && raw != "false" && raw != "null"
+}
+
+func (s *Schema) alternatives() []*Schema {
+	return append(append([]*Schema{}, s.OneOf...), s.AnyOf...)
+}
+
+// Fields of such objects aren't known upfront, i.e. any fields are preserved,
+// embedded resource is a whole Kubernetes object (with its own apiVersion & kind)
+func (s *Schema) isFreeForm() bool {
+	return s.PreserveUnknownFields || s.EmbeddedResource
+}
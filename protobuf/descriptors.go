@@ -0,0 +1,84 @@
+package protobuf
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	// Well-known types, in case these were left out of the set
+	_ "google.golang.org/protobuf/types/known/anypb"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// LoadFile reads a FileDescriptorSet, e.g. one produced by
+// protoc --include_imports --include_source_info --descriptor_set_out=FILE
+func LoadFile(path string) (*protoregistry.Files, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Parse decodes a binary FileDescriptorSet, imports missing from the set
+// are looked up among files linked into the binary (i.e. well-known types)
+func Parse(b []byte) (*protoregistry.Files, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, set); err != nil {
+		return nil, err
+	}
+	if len(set.File) == 0 {
+		return nil, fmt.Errorf("No files found in the descriptor set")
+	}
+
+	if err := addMissingImports(set); err != nil {
+		return nil, err
+	}
+	return protodesc.NewFiles(set)
+}
+
+func addMissingImports(set *descriptorpb.FileDescriptorSet) error {
+	found := make(map[string]bool, len(set.File))
+	for _, f := range set.File {
+		found[f.GetName()] = true
+	}
+
+	for i := 0; i < len(set.File); i++ {
+		for _, dep := range set.File[i].Dependency {
+			if found[dep] {
+				continue
+			}
+			fd, err := protoregistry.GlobalFiles.FindFileByPath(dep)
+			if err != nil {
+				return fmt.Errorf("Import %q of %q not found (missing --include_imports?)",
+					dep, set.File[i].GetName())
+			}
+			set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+			found[dep] = true
+		}
+	}
+	return nil
+}
+
+// Message looks up a message by its full name, e.g. acme.compute.v1.Instance
+func Message(files *protoregistry.Files, name string) (protoreflect.MessageDescriptor, error) {
+	d, err := files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("Message %q not found: %s", name, err)
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a message", name)
+	}
+	return md, nil
+}
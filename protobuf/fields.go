@@ -0,0 +1,371 @@
+package protobuf
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/schemagen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Generator builds the same field model as schemagen.SchemaGenerator,
+// but from message descriptors instead of (generated) Go structs.
+// Enum fields only accept declared value names, except the *_UNSPECIFIED one.
+type Generator struct {
+	schemagen.HelperFuncSet
+
+	Files *protoregistry.Files
+
+	msgStack []protoreflect.FullName
+}
+
+// FieldsFromMessage builds the schema tree (sorted by field name)
+// which may be rendered via schemagen.RenderFields
+func (g *Generator) FieldsFromMessage(name string) ([]*schemagen.Field, error) {
+	if g.Files == nil {
+		return nil, fmt.Errorf("No descriptors to look up %q in", name)
+	}
+	md, err := Message(g.Files, name)
+	if err != nil {
+		return nil, err
+	}
+	return g.FieldsFromDescriptor(md)
+}
+
+func (g *Generator) FieldsFromDescriptor(md protoreflect.MessageDescriptor) ([]*schemagen.Field, error) {
+	return g.fieldsFromMessage(md, "", true)
+}
+
+// Members of a oneof conflict with each other, which can only be expressed
+// if the block is addressable, i.e. top-level or nested with MaxItems: 1
+func (g *Generator) fieldsFromMessage(md protoreflect.MessageDescriptor, prefix string, addressable bool) ([]*schemagen.Field, error) {
+	for _, name := range g.msgStack {
+		if name == md.FullName() {
+			return nil, fmt.Errorf("Cycle detected: %s -> %s", joinNames(g.msgStack), name)
+		}
+	}
+	g.msgStack = append(g.msgStack, md.FullName())
+	defer func() {
+		g.msgStack = g.msgStack[:len(g.msgStack)-1]
+	}()
+
+	fds := md.Fields()
+	fields := make([]*schemagen.Field, 0, fds.Len())
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		f, err := g.generateField(fd, prefix, addressable)
+		if err != nil {
+			log.Printf("ERROR: %s", err)
+			continue
+		}
+		f.Name = string(fd.Name())
+
+		if oneof := fd.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() && addressable {
+			f.Schema.ConflictsWith = conflictingFields(fd, prefix)
+		}
+		fields = append(fields, f)
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+	return fields, nil
+}
+
+func (g *Generator) generateField(fd protoreflect.FieldDescriptor, prefix string, addressable bool) (*schemagen.Field, error) {
+	var f *schemagen.Field
+	var err error
+	switch {
+	case fd.IsMap():
+		f, err = g.generateMap(fd)
+	case fd.IsList():
+		f, err = g.generateList(fd)
+	default:
+		f, err = g.generateValue(fd, prefix+string(fd.Name())+".0.", addressable)
+		if err == nil && f.Block != nil {
+			f.Schema.Type = schema.TypeList
+			f.Schema.MaxItems = 1
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unable to process %q: %s", fd.FullName(), err)
+	}
+
+	s := f.Schema
+	s.Description = strings.TrimSpace(comments(fd) + " " + s.Description)
+
+	behavior := fieldBehavior(fd)
+	switch {
+	case behavior[outputOnly]:
+		s.Computed = true
+	case fd.Cardinality() == protoreflect.Required || behavior[required]:
+		s.Required = true
+	default:
+		s.Optional = true
+	}
+	if behavior[immutable] && !s.Computed {
+		s.ForceNew = true
+	}
+
+	// proto2 defaults only apply to fields the user may set
+	if fd.HasDefault() && !s.Computed && f.Block == nil {
+		s.Default = defaultValue(fd)
+		s.Required = false
+		s.Optional = true
+		schemagen.AppendDescription(s, fmt.Sprintf("Defaults to `%v`.", s.Default))
+	}
+	return f, nil
+}
+
+// Repeated fields keep their order, hence TypeList
+func (g *Generator) generateList(fd protoreflect.FieldDescriptor) (*schemagen.Field, error) {
+	elem, err := g.generateValue(fd, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &schemagen.Field{
+		Schema: &schema.Schema{Type: schema.TypeList},
+		Funcs:  &schemagen.SchemaFuncs{},
+	}
+	if elem.Block != nil {
+		f.Block = elem.Block
+		return f, nil
+	}
+	f.Elem = elem
+	liftDescription(f, elem)
+	return f, nil
+}
+
+// Only maps of primitives are supported by TypeMap
+func (g *Generator) generateMap(fd protoreflect.FieldDescriptor) (*schemagen.Field, error) {
+	elem, err := g.generateValue(fd.MapValue(), "", false)
+	if err != nil {
+		return nil, err
+	}
+	if elem.Block != nil {
+		return nil, fmt.Errorf("Maps of messages are not supported")
+	}
+
+	f := &schemagen.Field{
+		Schema: &schema.Schema{Type: schema.TypeMap},
+		Funcs:  &schemagen.SchemaFuncs{},
+	}
+	f.Elem = elem
+	liftDescription(f, elem)
+	return f, nil
+}
+
+// Required/Optional/Computed is derived from field_behavior by generateField
+func (g *Generator) generateValue(fd protoreflect.FieldDescriptor, prefix string, addressable bool) (*schemagen.Field, error) {
+	s := &schema.Schema{}
+	f := &schemagen.Field{Schema: s, Funcs: &schemagen.SchemaFuncs{}}
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		s.Type = schema.TypeBool
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		s.Type = schema.TypeInt
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		s.Type = schema.TypeFloat
+	case protoreflect.StringKind:
+		s.Type = schema.TypeString
+	case protoreflect.BytesKind:
+		// Base64-encoded, as in the JSON mapping
+		s.Type = schema.TypeString
+		f.Funcs.ValidateFunc = g.DeclareHelperFunc("validateBase64")
+	case protoreflect.EnumKind:
+		s.Type = schema.TypeString
+		validateFunc, values := enumValidateFunc(fd.Enum())
+		f.Funcs.ValidateFunc = validateFunc
+		s.Description = "Possible values: " + values + "."
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if g.wellKnownType(fd.Message(), f) {
+			break
+		}
+		fields, err := g.fieldsFromMessage(fd.Message(), prefix, addressable)
+		if err != nil {
+			return nil, err
+		}
+		f.Block = &schemagen.Block{Fields: fields}
+	default:
+		return nil, fmt.Errorf("Unknown kind %s", fd.Kind())
+	}
+	return f, nil
+}
+
+// Well-known types are represented the way these are mapped to JSON
+func (g *Generator) wellKnownType(md protoreflect.MessageDescriptor, f *schemagen.Field) bool {
+	s := f.Schema
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		s.Type = schema.TypeString
+		f.Funcs.ValidateFunc = g.DeclareHelperFunc("validateRFC3339Time")
+	case "google.protobuf.Duration":
+		s.Type = schema.TypeString
+		f.Funcs.ValidateFunc = g.DeclareHelperFunc("validateDuration")
+	case "google.protobuf.BoolValue":
+		s.Type = schema.TypeBool
+	case "google.protobuf.Int32Value", "google.protobuf.Int64Value",
+		"google.protobuf.UInt32Value", "google.protobuf.UInt64Value":
+		s.Type = schema.TypeInt
+	case "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		s.Type = schema.TypeFloat
+	case "google.protobuf.StringValue":
+		s.Type = schema.TypeString
+	case "google.protobuf.BytesValue":
+		s.Type = schema.TypeString
+		f.Funcs.ValidateFunc = g.DeclareHelperFunc("validateBase64")
+	case "google.protobuf.FieldMask":
+		// Comma-separated paths
+		s.Type = schema.TypeString
+	case "google.protobuf.Struct", "google.protobuf.Value",
+		"google.protobuf.ListValue", "google.protobuf.Any":
+		s.Type = schema.TypeString
+		f.Funcs.StateFunc = g.DeclareHelperFunc("normalizeJSONString")
+		f.Funcs.DiffSuppressFunc = g.DeclareHelperFunc("suppressEquivalentJSONDiffs")
+	default:
+		return false
+	}
+	return true
+}
+
+// The zero value of proto3 enums (e.g. STATE_UNSPECIFIED) stands for unset
+func enumValidateFunc(ed protoreflect.EnumDescriptor) (string, string) {
+	vds := ed.Values()
+	values := make([]string, 0, vds.Len())
+	docs := make([]string, 0, vds.Len())
+	for i := 0; i < vds.Len(); i++ {
+		vd := vds.Get(i)
+		if vd.Number() == 0 && strings.HasSuffix(string(vd.Name()), "_UNSPECIFIED") {
+			continue
+		}
+		values = append(values, fmt.Sprintf("%q", vd.Name()))
+		docs = append(docs, fmt.Sprintf("`%s`", vd.Name()))
+	}
+	return fmt.Sprintf("validation.StringInSlice([]string{%s}, false)", strings.Join(values, ", ")),
+		strings.Join(docs, ", ")
+}
+
+func defaultValue(fd protoreflect.FieldDescriptor) interface{} {
+	v := fd.Default()
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return v.Bool()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return int(v.Int())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return int(v.Uint())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float()
+	case protoreflect.EnumKind:
+		return string(fd.DefaultEnumValue().Name())
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	}
+	return v.String()
+}
+
+func conflictingFields(fd protoreflect.FieldDescriptor, prefix string) []string {
+	members := fd.ContainingOneof().Fields()
+	conflicts := make([]string, 0, members.Len()-1)
+	for i := 0; i < members.Len(); i++ {
+		if member := members.Get(i); member.Number() != fd.Number() {
+			conflicts = append(conflicts, prefix+string(member.Name()))
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// Values of google.api.field_behavior
+const (
+	required   = 2
+	outputOnly = 3
+	immutable  = 5
+)
+
+const fieldBehaviorExtension = 1052
+
+// fieldBehavior decodes the google.api.field_behavior option (if any),
+// the extension is read from raw options as it's unlikely to be linked in
+func fieldBehavior(fd protoreflect.FieldDescriptor) map[int]bool {
+	behavior := make(map[int]bool, 0)
+	b, err := proto.Marshal(fd.Options())
+	if err != nil {
+		return behavior
+	}
+
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return behavior
+		}
+		b = b[n:]
+
+		if num == fieldBehaviorExtension && typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return behavior
+			}
+			behavior[int(v)] = true
+			b = b[n:]
+			continue
+		}
+		if num == fieldBehaviorExtension && typ == protowire.BytesType {
+			packed, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return behavior
+			}
+			for len(packed) > 0 {
+				v, m := protowire.ConsumeVarint(packed)
+				if m < 0 {
+					break
+				}
+				behavior[int(v)] = true
+				packed = packed[m:]
+			}
+			b = b[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return behavior
+		}
+		b = b[n:]
+	}
+	return behavior
+}
+
+// Leading comments require the set to be produced with --include_source_info
+func comments(d protoreflect.Descriptor) string {
+	loc := d.ParentFile().SourceLocations().ByDescriptor(d)
+	return strings.Join(strings.Fields(loc.LeadingComments), " ")
+}
+
+// Description belongs to the list/map, not to its elements
+func liftDescription(f, elem *schemagen.Field) {
+	f.Schema.Description = elem.Schema.Description
+	elem.Schema.Description = ""
+}
+
+func joinNames(names []protoreflect.FullName) string {
+	s := make([]string, len(names))
+	for i, name := range names {
+		s[i] = string(name)
+	}
+	return strings.Join(s, " -> ")
+}
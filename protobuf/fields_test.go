@@ -0,0 +1,176 @@
+package protobuf
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/radeksimko/terraform-gen/schemagen"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Equivalent of protoc --descriptor_set_out for acme/compute/v1/instance.proto,
+// imports are left out intentionally
+const instanceDescriptorSet = `file: {
+  name: "acme/compute/v1/instance.proto"
+  package: "acme.compute.v1"
+  dependency: "google/protobuf/timestamp.proto"
+  dependency: "google/protobuf/duration.proto"
+  dependency: "google/protobuf/wrappers.proto"
+  dependency: "google/protobuf/struct.proto"
+  syntax: "proto3"
+  message_type: {
+    name: "Instance"
+    field: { name: "name" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+    field: { name: "state" number: 2 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".acme.compute.v1.Instance.State" }
+    field: { name: "create_time" number: 3 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Timestamp" }
+    field: { name: "timeout" number: 4 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Duration" }
+    field: { name: "labels" number: 5 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".acme.compute.v1.Instance.LabelsEntry" }
+    field: { name: "disks" number: 6 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".acme.compute.v1.Disk" }
+    field: { name: "zones" number: 7 label: LABEL_REPEATED type: TYPE_STRING }
+    field: { name: "image" number: 8 label: LABEL_OPTIONAL type: TYPE_STRING oneof_index: 0 }
+    field: { name: "snapshot" number: 9 label: LABEL_OPTIONAL type: TYPE_STRING oneof_index: 0 }
+    field: { name: "cpus" number: 10 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Int32Value" }
+    field: { name: "metadata" number: 11 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Struct" }
+    field: { name: "network" number: 12 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".acme.compute.v1.Network" }
+    field: { name: "parent" number: 13 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".acme.compute.v1.Instance" }
+    nested_type: {
+      name: "LabelsEntry"
+      field: { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+      field: { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+      options: { map_entry: true }
+    }
+    enum_type: {
+      name: "State"
+      value: { name: "STATE_UNSPECIFIED" number: 0 }
+      value: { name: "RUNNING" number: 1 }
+      value: { name: "STOPPED" number: 2 }
+    }
+    oneof_decl: { name: "source" }
+  }
+  message_type: {
+    name: "Disk"
+    field: { name: "size_gb" number: 1 label: LABEL_OPTIONAL type: TYPE_INT64 }
+  }
+  message_type: {
+    name: "Network"
+    field: { name: "subnet" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING oneof_index: 0 }
+    field: { name: "vpc" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING oneof_index: 0 }
+    oneof_decl: { name: "target" }
+  }
+  source_code_info: {
+    location: { path: [4, 0, 2, 0] span: [10, 2, 20] leading_comments: " Name of the instance,\n unique within the project.\n" }
+  }
+}`
+
+func TestFieldsFromMessage(t *testing.T) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := prototext.Unmarshal([]byte(instanceDescriptorSet), set); err != nil {
+		t.Fatal(err)
+	}
+	instance := set.File[0].MessageType[0]
+	setFieldBehavior(instance.Field[0], required, immutable)
+	setFieldBehavior(instance.Field[2], outputOnly)
+
+	b, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Generator{Files: files}
+	fields, err := g.FieldsFromMessage("acme.compute.v1.Instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := schemagen.RenderFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"cpus":        "{\nType: schema.TypeInt,\nOptional: true,\n}",
+		"create_time": "{\nType: schema.TypeString,\nComputed: true,\nValidateFunc: validateRFC3339Time,\n}",
+		"disks":       "{\nType: schema.TypeList,\nOptional: true,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"size_gb\": {\nType: schema.TypeInt,\nOptional: true,\n},\n},\n},\n}",
+		"image":       "{\nType: schema.TypeString,\nOptional: true,\nConflictsWith: []string{\"snapshot\"},\n}",
+		"labels":      "{\nType: schema.TypeMap,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\n}",
+		"metadata":    "{\nType: schema.TypeString,\nOptional: true,\nStateFunc: normalizeJSONString,\nDiffSuppressFunc: suppressEquivalentJSONDiffs,\n}",
+		"name":        "{\nType: schema.TypeString,\nDescription: \"Name of the instance, unique within the project.\",\nRequired: true,\nForceNew: true,\n}",
+		"network":     "{\nType: schema.TypeList,\nOptional: true,\nMaxItems: 1,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"subnet\": {\nType: schema.TypeString,\nOptional: true,\nConflictsWith: []string{\"network.0.vpc\"},\n},\n\"vpc\": {\nType: schema.TypeString,\nOptional: true,\nConflictsWith: []string{\"network.0.subnet\"},\n},\n},\n},\n}",
+		"snapshot":    "{\nType: schema.TypeString,\nOptional: true,\nConflictsWith: []string{\"image\"},\n}",
+		"state":       "{\nType: schema.TypeString,\nDescription: \"Possible values: `RUNNING`, `STOPPED`.\",\nOptional: true,\nValidateFunc: validation.StringInSlice([]string{\"RUNNING\", \"STOPPED\"}, false),\n}",
+		"timeout":     "{\nType: schema.TypeString,\nOptional: true,\nValidateFunc: validateDuration,\n}",
+		"zones":       "{\nType: schema.TypeList,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\n}",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %#v\n\nGiven: %#v\n", expectedOutput, output)
+	}
+
+	funcs := g.SchemaFunctions()
+	if _, ok := funcs["validateDuration"]; !ok {
+		t.Fatalf("Expected validateDuration to be declared, given: %q", funcs)
+	}
+}
+
+const proto2DescriptorSet = `file: {
+  name: "acme/storage/v1/blob.proto"
+  package: "acme.storage.v1"
+  syntax: "proto2"
+  message_type: {
+    name: "Blob"
+    field: { name: "content" number: 1 label: LABEL_OPTIONAL type: TYPE_BYTES default_value: "\\001ab" }
+    field: { name: "size" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32 default_value: "3" }
+  }
+}`
+
+func TestFieldsFromMessage_proto2Defaults(t *testing.T) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := prototext.Unmarshal([]byte(proto2DescriptorSet), set); err != nil {
+		t.Fatal(err)
+	}
+	b, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Generator{Files: files}
+	fields, err := g.FieldsFromMessage("acme.storage.v1.Blob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := schemagen.RenderFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"content": "{\nType: schema.TypeString,\nDescription: \"Defaults to `AWFi`.\",\nOptional: true,\nDefault: \"AWFi\",\nValidateFunc: validateBase64,\n}",
+		"size":    "{\nType: schema.TypeInt,\nDescription: \"Defaults to `3`.\",\nOptional: true,\nDefault: 3,\n}",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %#v\n\nGiven: %#v\n", expectedOutput, output)
+	}
+
+	funcs := g.SchemaFunctions()
+	if _, ok := funcs["validateBase64"]; !ok {
+		t.Fatalf("Expected validateBase64 to be declared, given: %q", funcs)
+	}
+}
+
+// google.api.field_behavior as it appears when annotations.proto isn't linked
+func setFieldBehavior(fd *descriptorpb.FieldDescriptorProto, values ...int) {
+	var b []byte
+	for _, v := range values {
+		b = protowire.AppendTag(b, fieldBehaviorExtension, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(v))
+	}
+	fd.Options = &descriptorpb.FieldOptions{}
+	fd.Options.ProtoReflect().SetUnknown(b)
+}
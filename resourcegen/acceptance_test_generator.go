@@ -0,0 +1,211 @@
+package resourcegen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+// Scaffolding of an acceptance test, expecting testAccPreCheck and testAccProviders
+// (testAccProviderFactories for SDKv2, testAccProtoV6ProviderFactories for Framework)
+// to exist in the target package
+type AcceptanceTest struct {
+	PkgName        string
+	ResourceKey    string
+	ResourceSchema *schema.Resource
+
+	// SDKv1 (default), SDKv2 or Framework
+	Backend schemagen.Backend
+}
+
+func (at *AcceptanceTest) Filename() string {
+	return fmt.Sprintf("resource_%s_test.go", at.ResourceKey)
+}
+
+func (at *AcceptanceTest) GenerateTestCode(wr io.Writer) error {
+	basic := &testConfig{}
+	basic.addFields(at.ResourceSchema.Schema, "", "  ", false)
+	modified := &testConfig{}
+	modified.addFields(at.ResourceSchema.Schema, "", "  ", true)
+
+	providers := testProviders[at.Backend]
+	name := u.Camelize(at.ResourceKey)
+	return acceptanceTestTemplate.Execute(wr, &acceptanceTestCode{
+		AcceptanceTest: at,
+		ImportPath:     at.Backend.TestingImportPath(),
+		PreCheckKey:    fmt.Sprintf("%-*s", len(providers[0])+1, "PreCheck:"),
+		ProvidersKey:   providers[0] + ":",
+		ProvidersVar:   providers[1],
+		TestName:       "TestAcc" + name,
+		ConfigName:     "testAcc" + name + "Config",
+		Basic:          basic,
+		Modified:       modified,
+		HasUpdate:      modified.HCL != basic.HCL,
+	})
+}
+
+// Field of resource.TestCase & the variable expected to hold providers
+var testProviders = map[schemagen.Backend][2]string{
+	schemagen.SDKv1:     {"Providers", "testAccProviders"},
+	schemagen.SDKv2:     {"ProviderFactories", "testAccProviderFactories"},
+	schemagen.Framework: {"ProtoV6ProviderFactories", "testAccProtoV6ProviderFactories"},
+}
+
+type acceptanceTestCode struct {
+	*AcceptanceTest
+
+	ImportPath string
+	// Aligned as by gofmt, e.g. "PreCheck: " & "Providers:"
+	PreCheckKey  string
+	ProvidersKey string
+	ProvidersVar string
+	TestName     string
+	ConfigName   string
+
+	Basic     *testConfig
+	Modified  *testConfig
+	HasUpdate bool
+}
+
+type testConfig struct {
+	HCL    string
+	Checks []*attributeCheck
+}
+
+type attributeCheck struct {
+	Key   string
+	Value string
+}
+
+// Required fields are always filled, Optional ones (unless ForceNew)
+// only in the modified config, so that the update step changes them
+func (c *testConfig) addFields(m map[string]*schema.Schema, keyPrefix, indent string, modified bool) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := m[k]
+		if !s.Required && !(modified && s.Optional && !s.ForceNew) {
+			continue
+		}
+		c.addField(k, s, keyPrefix, indent, modified)
+	}
+}
+
+func (c *testConfig) addField(k string, s *schema.Schema, keyPrefix, indent string, modified bool) {
+	switch s.Type {
+	case schema.TypeString, schema.TypeInt, schema.TypeFloat, schema.TypeBool:
+		hcl, raw := sampleValue(s.Type)
+		c.HCL += fmt.Sprintf("%s%s = %s\n", indent, k, hcl)
+		c.check(keyPrefix+k, raw)
+	case schema.TypeMap:
+		c.HCL += fmt.Sprintf("%s%s = {\n%s  foo = \"bar\"\n%s}\n", indent, k, indent, indent)
+		c.check(keyPrefix+k+".%", "1")
+		c.check(keyPrefix+k+".foo", "bar")
+	case schema.TypeList, schema.TypeSet:
+		c.check(keyPrefix+k+".#", "1")
+		switch elem := s.Elem.(type) {
+		case *schema.Resource:
+			c.HCL += fmt.Sprintf("%s%s {\n", indent, k)
+			if s.Type == schema.TypeList {
+				c.addFields(elem.Schema, keyPrefix+k+".0.", indent+"  ", modified)
+			} else {
+				// Elements of a set are addressed by hash which isn't known upfront
+				nested := &testConfig{}
+				nested.addFields(elem.Schema, "", indent+"  ", modified)
+				c.HCL += nested.HCL
+			}
+			c.HCL += indent + "}\n"
+		case *schema.Schema:
+			hcl, _ := sampleValue(elem.Type)
+			c.HCL += fmt.Sprintf("%s%s = [%s]\n", indent, k, hcl)
+		}
+	}
+}
+
+func (c *testConfig) check(key, value string) {
+	c.Checks = append(c.Checks, &attributeCheck{Key: key, Value: value})
+}
+
+// Returns the value as it appears in HCL & in the state
+func sampleValue(t schema.ValueType) (string, string) {
+	switch t {
+	case schema.TypeInt:
+		return "1", "1"
+	case schema.TypeFloat:
+		return "1.5", "1.5"
+	case schema.TypeBool:
+		return "true", "true"
+	}
+	return `"tf-acc-test"`, "tf-acc-test"
+}
+
+func (c *testConfig) Body() string {
+	return strings.TrimSuffix(c.HCL, "\n")
+}
+
+var acceptanceTestTemplate = template.Must(template.New("acceptance-test").Parse(`package {{.PkgName}}
+
+import (
+	"testing"
+
+	"{{.ImportPath}}"
+)
+
+func {{.TestName}}_basic(t *testing.T) {
+	resourceName := "{{.ResourceKey}}.test"
+
+	resource.Test(t, resource.TestCase{
+		{{.PreCheckKey}} func() { testAccPreCheck(t) },
+		{{.ProvidersKey}} {{.ProvidersVar}},
+		Steps: []resource.TestStep{
+			{
+				Config: {{.ConfigName}}_basic,
+				Check: resource.ComposeTestCheckFunc(
+{{- range .Basic.Checks}}
+					resource.TestCheckResourceAttr(resourceName, {{printf "%q" .Key}}, {{printf "%q" .Value}}),
+{{- end}}
+				),
+			},
+{{- if .HasUpdate}}
+			{
+				Config: {{.ConfigName}}_modified,
+				Check: resource.ComposeTestCheckFunc(
+{{- range .Modified.Checks}}
+					resource.TestCheckResourceAttr(resourceName, {{printf "%q" .Key}}, {{printf "%q" .Value}}),
+{{- end}}
+				),
+			},
+{{- end}}
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const {{.ConfigName}}_basic = ` + "`" + `
+resource "{{.ResourceKey}}" "test" {
+{{.Basic.Body}}
+}
+` + "`" + `
+{{- if .HasUpdate}}
+
+const {{.ConfigName}}_modified = ` + "`" + `
+resource "{{.ResourceKey}}" "test" {
+{{.Modified.Body}}
+}
+` + "`" + `
+{{- end}}
+`))
@@ -0,0 +1,199 @@
+package resourcegen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+func TestGenerateTestCode_basic(t *testing.T) {
+	at := &AcceptanceTest{
+		PkgName:     "cattle",
+		ResourceKey: "cattle_cow",
+		ResourceSchema: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"metadata": {
+					Type:     schema.TypeList,
+					Required: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:     schema.TypeString,
+								Required: true,
+								ForceNew: true,
+							},
+							"labels": {
+								Type:     schema.TypeMap,
+								Optional: true,
+							},
+						},
+					},
+				},
+				"age": {
+					Type:     schema.TypeInt,
+					Optional: true,
+				},
+				"breed": {
+					Type:     schema.TypeString,
+					Optional: true,
+					ForceNew: true,
+				},
+				"tags": {
+					Type:     schema.TypeSet,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"uid": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	if at.Filename() != "resource_cattle_cow_test.go" {
+		t.Fatalf("Unexpected filename: %q", at.Filename())
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := at.GenerateTestCode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	if output != acceptance_test_basic_output {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", acceptance_test_basic_output, output)
+	}
+}
+
+var acceptance_test_basic_output = `package cattle
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccCattleCow_basic(t *testing.T) {
+	resourceName := "cattle_cow.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCattleCowConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "metadata.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.0.name", "tf-acc-test"),
+				),
+			},
+			{
+				Config: testAccCattleCowConfig_modified,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "age", "1"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.0.labels.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.0.labels.foo", "bar"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.0.name", "tf-acc-test"),
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "1"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccCattleCowConfig_basic = ` + "`" + `
+resource "cattle_cow" "test" {
+  metadata {
+    name = "tf-acc-test"
+  }
+}
+` + "`" + `
+
+const testAccCattleCowConfig_modified = ` + "`" + `
+resource "cattle_cow" "test" {
+  age = 1
+  metadata {
+    labels = {
+      foo = "bar"
+    }
+    name = "tf-acc-test"
+  }
+  tags = ["tf-acc-test"]
+}
+` + "`" + `
+`
+
+func TestGenerateTestCode_framework(t *testing.T) {
+	at := &AcceptanceTest{
+		PkgName:     "cattle",
+		ResourceKey: "cattle_cow",
+		Backend:     schemagen.Framework,
+		ResourceSchema: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+		},
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := at.GenerateTestCode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	if output != acceptance_test_framework_output {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", acceptance_test_framework_output, output)
+	}
+}
+
+var acceptance_test_framework_output = `package cattle
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCattleCow_basic(t *testing.T) {
+	resourceName := "cattle_cow.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCattleCowConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "tf-acc-test"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccCattleCowConfig_basic = ` + "`" + `
+resource "cattle_cow" "test" {
+  name = "tf-acc-test"
+}
+` + "`" + `
+`
@@ -0,0 +1,131 @@
+package resourcegen
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"text/template"
+
+	"github.com/radeksimko/terraform-gen/helpergen"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+type DataSource struct {
+	PkgName       string
+	DataSourceKey string
+
+	// SDKv1 (default), SDKv2 or Framework
+	Backend schemagen.Backend
+
+	// SDK struct the schema (see SchemaGenerator.DataSource) & flatteners were generated from
+	SDKType interface{}
+	// Name of the variable holding the generated map[string]*schema.Schema
+	// (datasource/schema.Schema for the framework backend, see schemagen.RenderFrameworkDataSourceSchema)
+	SchemaVarName string
+	// Framework model generated via helpergen.ModelsFromStruct, defaults to helpergen.ModelName(SDKType),
+	// it's embedded in the data source's model along with the ID
+	ModelName string
+
+	// Type of the provider's meta, asserted & available as conn in ReadCall
+	ClientType string
+
+	// Expected to return (object, error), may refer to any lookup argument
+	// by its placeholder from IDFormat, e.g. {metadata.0.name}
+	ReadCall string
+
+	// Placeholders are the lookup arguments, e.g. {metadata.0.namespace}/{metadata.0.name}
+	IDFormat string
+}
+
+func (ds *DataSource) Filename() string {
+	return fmt.Sprintf("data_source_%s.go", ds.DataSourceKey)
+}
+
+func (ds *DataSource) GenerateDataSourceCode(wr io.Writer) error {
+	id, err := parseIDFormat(ds.IDFormat)
+	if err != nil {
+		return err
+	}
+
+	modelName := frameworkModelName(ds.ModelName, ds.SDKType)
+	dsc := &dataSourceCode{
+		DataSource:      ds,
+		FuncName:        "dataSource" + u.Camelize(ds.DataSourceKey),
+		TypeName:        u.LowerFirst(u.Camelize(ds.DataSourceKey)) + "DataSource",
+		ConstructorName: "New" + u.Camelize(ds.DataSourceKey) + "DataSource",
+		ModelName:       modelName,
+		SDKValue:        "obj",
+		FlattenerName:   helpergen.FlattenerFuncName(ds.SDKType),
+		ID:              id,
+		Read:            id.clientCall(ds.ReadCall),
+	}
+
+	if reflect.TypeOf(ds.SDKType).Kind() == reflect.Ptr {
+		dsc.SDKValue = "*obj"
+	}
+
+	switch ds.Backend {
+	case schemagen.SDKv2:
+		return sdkv2DataSourceTemplate.Execute(wr, dsc)
+	case schemagen.Framework:
+		return frameworkDataSourceTemplate.Execute(wr, dsc)
+	}
+	return dataSourceTemplate.Execute(wr, dsc)
+}
+
+type dataSourceCode struct {
+	*DataSource
+
+	FuncName        string
+	TypeName        string
+	ConstructorName string
+	ModelName       string
+	// SDK struct passed to the model's fromSDK, e.g. *obj
+	SDKValue      string
+	FlattenerName string
+
+	ID   *idFormat
+	Read *clientCall
+}
+
+var dataSourceTemplate = template.Must(template.New("data-source").Parse(`package {{.PkgName}}
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func {{.FuncName}}() *schema.Resource {
+	return &schema.Resource{
+		Read: {{.FuncName}}Read,
+
+		Schema: {{.SchemaVarName}},
+	}
+}
+
+func {{.FuncName}}Read(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.({{.ClientType}})
+{{range .ID.Parts}}
+	{{.VarName}} := d.Get({{printf "%q" .Path}}).(string)
+{{- end}}
+	id := {{.ID.JoinedVarNames}}
+
+	log.Printf("[INFO] Reading {{.DataSourceKey}} %s", id)
+	obj, err := {{.Read.Code}}
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Received {{.DataSourceKey}}: %#v", obj)
+
+	for k, v := range {{.FlattenerName}}(obj)[0].(map[string]interface{}) {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+	d.SetId(id)
+
+	return nil
+}
+`))
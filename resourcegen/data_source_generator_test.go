@@ -0,0 +1,241 @@
+package resourcegen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+func TestGenerateDataSourceCode_basic(t *testing.T) {
+	ds := &DataSource{
+		PkgName:       "kubernetes",
+		DataSourceKey: "kubernetes_config_map",
+		SDKType:       ConfigMap{},
+		SchemaVarName: "configMapDataSourceSchema",
+		ClientType:    "*kubernetes.Clientset",
+		ReadCall:      "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Get({metadata.0.name})",
+		IDFormat:      "{metadata.0.namespace}/{metadata.0.name}",
+	}
+
+	if ds.Filename() != "data_source_kubernetes_config_map.go" {
+		t.Fatalf("Unexpected filename: %q", ds.Filename())
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := ds.GenerateDataSourceCode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	if output != data_source_basic_output {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", data_source_basic_output, output)
+	}
+}
+
+var data_source_basic_output = `package kubernetes
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceKubernetesConfigMap() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceKubernetesConfigMapRead,
+
+		Schema: configMapDataSourceSchema,
+	}
+}
+
+func dataSourceKubernetesConfigMapRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*kubernetes.Clientset)
+
+	namespace := d.Get("metadata.0.namespace").(string)
+	name := d.Get("metadata.0.name").(string)
+	id := namespace + "/" + name
+
+	log.Printf("[INFO] Reading kubernetes_config_map %s", id)
+	obj, err := conn.CoreV1().ConfigMaps(namespace).Get(name)
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Received kubernetes_config_map: %#v", obj)
+
+	for k, v := range flattenConfigMap(obj)[0].(map[string]interface{}) {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+	d.SetId(id)
+
+	return nil
+}
+`
+
+func TestGenerateDataSourceCode_sdkv2(t *testing.T) {
+	ds := &DataSource{
+		PkgName:       "kubernetes",
+		DataSourceKey: "kubernetes_config_map",
+		Backend:       schemagen.SDKv2,
+		SDKType:       ConfigMap{},
+		SchemaVarName: "configMapDataSourceSchema",
+		ClientType:    "*kubernetes.Clientset",
+		ReadCall:      "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Get({metadata.0.name})",
+		IDFormat:      "{metadata.0.namespace}/{metadata.0.name}",
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := ds.GenerateDataSourceCode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	if output != data_source_sdkv2_output {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", data_source_sdkv2_output, output)
+	}
+}
+
+var data_source_sdkv2_output = `package kubernetes
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceKubernetesConfigMap() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceKubernetesConfigMapRead,
+
+		Schema: configMapDataSourceSchema,
+	}
+}
+
+func dataSourceKubernetesConfigMapRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*kubernetes.Clientset)
+
+	namespace := d.Get("metadata.0.namespace").(string)
+	name := d.Get("metadata.0.name").(string)
+	id := namespace + "/" + name
+
+	log.Printf("[INFO] Reading kubernetes_config_map %s", id)
+	obj, err := conn.CoreV1().ConfigMaps(namespace).Get(name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Received kubernetes_config_map: %#v", obj)
+
+	for k, v := range flattenConfigMap(obj)[0].(map[string]interface{}) {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	d.SetId(id)
+
+	return nil
+}
+`
+
+func TestGenerateDataSourceCode_framework(t *testing.T) {
+	ds := &DataSource{
+		PkgName:       "kubernetes",
+		DataSourceKey: "kubernetes_config_map",
+		Backend:       schemagen.Framework,
+		SDKType:       ConfigMap{},
+		SchemaVarName: "configMapDataSourceSchema",
+		ClientType:    "*kubernetes.Clientset",
+		ReadCall:      "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Get({metadata.0.name})",
+		IDFormat:      "{metadata.0.namespace}/{metadata.0.name}",
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := ds.GenerateDataSourceCode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	if output != data_source_framework_output {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", data_source_framework_output, output)
+	}
+}
+
+var data_source_framework_output = `package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSourceWithConfigure = &kubernetesConfigMapDataSource{}
+
+func NewKubernetesConfigMapDataSource() datasource.DataSource {
+	return &kubernetesConfigMapDataSource{}
+}
+
+type kubernetesConfigMapDataSource struct {
+	conn *kubernetes.Clientset
+}
+
+type kubernetesConfigMapDataSourceModel struct {
+	Id types.String ` + "`" + `tfsdk:"id"` + "`" + `
+	ConfigMapModel
+}
+
+func (d *kubernetesConfigMapDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = "kubernetes_config_map"
+}
+
+func (d *kubernetesConfigMapDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = configMapDataSourceSchema
+}
+
+func (d *kubernetesConfigMapDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	conn, ok := req.ProviderData.(*kubernetes.Clientset)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data",
+			fmt.Sprintf("Expected *kubernetes.Clientset, given: %T", req.ProviderData))
+		return
+	}
+	d.conn = conn
+}
+
+func (d *kubernetesConfigMapDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	conn := d.conn
+
+	var namespace string
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("metadata").AtListIndex(0).AtName("namespace"), &namespace)...)
+	var name string
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("metadata").AtListIndex(0).AtName("name"), &name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	id := namespace + "/" + name
+
+	log.Printf("[INFO] Reading kubernetes_config_map %s", id)
+	obj, err := conn.CoreV1().ConfigMaps(namespace).Get(name)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading kubernetes_config_map", err.Error())
+		return
+	}
+	log.Printf("[INFO] Received kubernetes_config_map: %#v", obj)
+
+	state := kubernetesConfigMapDataSourceModel{Id: types.StringValue(id)}
+	state.fromSDK(obj)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+`
@@ -0,0 +1,282 @@
+package resourcegen
+
+import (
+	"text/template"
+)
+
+var frameworkResourceTemplate = template.Must(template.New("resource-framework").Parse(`package {{.PkgName}}
+
+import (
+	"context"
+	"fmt"
+	"log"
+{{- if gt (len .ID.Parts) 1}}
+	"strings"
+{{- end}}
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &{{.TypeName}}{}
+	_ resource.ResourceWithConfigure   = &{{.TypeName}}{}
+	_ resource.ResourceWithImportState = &{{.TypeName}}{}
+)
+
+func {{.ConstructorName}}() resource.Resource {
+	return &{{.TypeName}}{}
+}
+
+type {{.TypeName}} struct {
+	conn {{.ClientType}}
+}
+
+type {{.TypeName}}Model struct {
+	Id types.String ` + "`" + `tfsdk:"id"` + "`" + `
+	{{.ModelName}}
+}
+
+func (r *{{.TypeName}}) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = {{printf "%q" .ResourceKey}}
+}
+
+func (r *{{.TypeName}}) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = {{.SchemaVarName}}
+}
+
+func (r *{{.TypeName}}) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	conn, ok := req.ProviderData.({{.ClientType}})
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data",
+			fmt.Sprintf("Expected {{.ClientType}}, given: %T", req.ProviderData))
+		return
+	}
+	r.conn = conn
+}
+
+func (r *{{.TypeName}}) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	conn := r.conn
+
+	var plan {{.TypeName}}Model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+{{- range .ID.Parts}}
+	var {{.VarName}} types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, {{.FrameworkPath}}, &{{.VarName}})...)
+{{- end}}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	obj := plan.toSDK()
+	log.Printf("[INFO] Creating new {{.ResourceKey}}: %#v", obj)
+	out, err := {{.Create.Code}}
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating {{.ResourceKey}}", err.Error())
+		return
+	}
+	log.Printf("[INFO] Submitted new {{.ResourceKey}}: %#v", out)
+
+	state, err := r.read(ctx, {{.BuildIDFuncName}}({{.ID.StringValues}}))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading {{.ResourceKey}}", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *{{.TypeName}}) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var id string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state, err := r.read(ctx, id)
+	if err != nil {
+{{- if .IsNotFoundFunc}}
+		if {{.IsNotFoundFunc}}(err) {
+			log.Printf("[WARN] {{.ResourceKey}} %s not found, removing from state", id)
+			resp.State.RemoveResource(ctx)
+			return
+		}
+{{- end}}
+		resp.Diagnostics.AddError("Error reading {{.ResourceKey}}", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *{{.TypeName}}) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+{{- if .Update}}
+	conn := r.conn
+
+	var plan {{.TypeName}}Model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	var id string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+{{- if .Update.IDVars}}
+
+	{{.Update.IDVars}}, err := {{.ParseIDFuncName}}(id)
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating {{.ResourceKey}}", err.Error())
+		return
+	}
+{{- end}}
+
+	obj := plan.toSDK()
+	log.Printf("[INFO] Updating {{.ResourceKey}} %s: %#v", id, obj)
+	out, err := {{.Update.Code}}
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating {{.ResourceKey}}", err.Error())
+		return
+	}
+	log.Printf("[INFO] Submitted updated {{.ResourceKey}}: %#v", out)
+
+	state, err := r.read(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading {{.ResourceKey}}", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+{{- else}}
+	resp.Diagnostics.AddError("Update not supported", "{{.ResourceKey}} can only be replaced")
+{{- end}}
+}
+
+func (r *{{.TypeName}}) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	conn := r.conn
+
+	var id string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+{{- if .Delete.IDVars}}
+
+	{{.Delete.IDVars}}, err := {{.ParseIDFuncName}}(id)
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting {{.ResourceKey}}", err.Error())
+		return
+	}
+{{- end}}
+
+	log.Printf("[INFO] Deleting {{.ResourceKey}}: %s", id)
+	if err := {{.Delete.Code}}; err != nil {
+		resp.Diagnostics.AddError("Error deleting {{.ResourceKey}}", err.Error())
+		return
+	}
+	log.Printf("[INFO] {{.ResourceKey}} %s deleted", id)
+}
+
+func (r *{{.TypeName}}) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if {{.ID.BlankVarNames}}, err := {{.ParseIDFuncName}}(req.ID); err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *{{.TypeName}}) read(ctx context.Context, id string) (*{{.TypeName}}Model, error) {
+	conn := r.conn
+{{- if .Read.IDVars}}
+
+	{{.Read.IDVars}}, err := {{.ParseIDFuncName}}(id)
+	if err != nil {
+		return nil, err
+	}
+{{- end}}
+
+	log.Printf("[INFO] Reading {{.ResourceKey}} %s", id)
+	obj, err := {{.Read.Code}}
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[INFO] Received {{.ResourceKey}}: %#v", obj)
+
+	state := &{{.TypeName}}Model{Id: types.StringValue(id)}
+	state.fromSDK({{.SDKValue}})
+	return state, nil
+}
+
+` + idFuncsCode))
+
+var frameworkDataSourceTemplate = template.Must(template.New("data-source-framework").Parse(`package {{.PkgName}}
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSourceWithConfigure = &{{.TypeName}}{}
+
+func {{.ConstructorName}}() datasource.DataSource {
+	return &{{.TypeName}}{}
+}
+
+type {{.TypeName}} struct {
+	conn {{.ClientType}}
+}
+
+type {{.TypeName}}Model struct {
+	Id types.String ` + "`" + `tfsdk:"id"` + "`" + `
+	{{.ModelName}}
+}
+
+func (d *{{.TypeName}}) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = {{printf "%q" .DataSourceKey}}
+}
+
+func (d *{{.TypeName}}) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = {{.SchemaVarName}}
+}
+
+func (d *{{.TypeName}}) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	conn, ok := req.ProviderData.({{.ClientType}})
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data",
+			fmt.Sprintf("Expected {{.ClientType}}, given: %T", req.ProviderData))
+		return
+	}
+	d.conn = conn
+}
+
+func (d *{{.TypeName}}) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	conn := d.conn
+{{range .ID.Parts}}
+	var {{.VarName}} string
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, {{.FrameworkPath}}, &{{.VarName}})...)
+{{- end}}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	id := {{.ID.JoinedVarNames}}
+
+	log.Printf("[INFO] Reading {{.DataSourceKey}} %s", id)
+	obj, err := {{.Read.Code}}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading {{.DataSourceKey}}", err.Error())
+		return
+	}
+	log.Printf("[INFO] Received {{.DataSourceKey}}: %#v", obj)
+
+	state := {{.TypeName}}Model{Id: types.StringValue(id)}
+	state.fromSDK({{.SDKValue}})
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+`))
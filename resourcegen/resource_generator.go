@@ -0,0 +1,540 @@
+package resourcegen
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/helpergen"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+type Resource struct {
+	PkgName     string
+	ResourceKey string
+
+	// SDKv1 (default), SDKv2 or Framework
+	Backend schemagen.Backend
+
+	// SDK struct the schema, expanders & flatteners were generated from
+	SDKType interface{}
+	// Name of the variable holding the generated map[string]*schema.Schema
+	// (schema.Schema for the framework backend, see schemagen.RenderFrameworkSchema)
+	SchemaVarName string
+	// Framework model generated via helpergen.ModelsFromStruct, defaults to helpergen.ModelName(SDKType),
+	// it's embedded in the resource's model along with the ID
+	ModelName string
+
+	// Type of the provider's meta, asserted & available as conn in all calls
+	ClientType string
+
+	// Client calls may refer to the expanded object as {obj}
+	// and to any part of the ID by its placeholder, e.g. {metadata.0.name}.
+	// Create, Read & Update are expected to return (object, error),
+	// Delete is expected to return just error.
+	CreateCall string
+	ReadCall   string
+	UpdateCall string
+	DeleteCall string
+
+	// e.g. errors.IsNotFound - optional, used in Exists (Read in other backends)
+	IsNotFoundFunc string
+
+	// Placeholders refer to (string) attributes, e.g. {metadata.0.namespace}/{metadata.0.name}
+	IDFormat string
+
+	// Schema of the previous release (e.g. from schemajson) & fields of the generated one,
+	// renamed, removed or retyped fields bump SchemaVersion (SDK backends only),
+	// see GenerateStateUpgraderCode
+	PreviousSchema *schema.Resource
+	SchemaFields   []*schemagen.Field
+}
+
+func (r *Resource) Filename() string {
+	return fmt.Sprintf("resource_%s.go", r.ResourceKey)
+}
+
+func (r *Resource) GenerateResourceCode(wr io.Writer) error {
+	rc, err := r.resourceCode()
+	if err != nil {
+		return err
+	}
+
+	switch r.Backend {
+	case schemagen.SDKv2:
+		return sdkv2ResourceTemplate.Execute(wr, rc)
+	case schemagen.Framework:
+		return frameworkResourceTemplate.Execute(wr, rc)
+	}
+	return resourceTemplate.Execute(wr, rc)
+}
+
+func (r *Resource) IDTestFilename() string {
+	return fmt.Sprintf("resource_%s_id_test.go", r.ResourceKey)
+}
+
+// Round-trip test of the generated ID parser & builder
+func (r *Resource) GenerateIDTestCode(wr io.Writer) error {
+	rc, err := r.resourceCode()
+	if err != nil {
+		return err
+	}
+	return idTestTemplate.Execute(wr, rc)
+}
+
+func (r *Resource) resourceCode() (*resourceCode, error) {
+	id, err := parseIDFormat(r.IDFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	funcName := "resource" + u.Camelize(r.ResourceKey)
+	modelName := frameworkModelName(r.ModelName, r.SDKType)
+	rc := &resourceCode{
+		Resource:        r,
+		FuncName:        funcName,
+		TypeName:        u.LowerFirst(u.Camelize(r.ResourceKey)) + "Resource",
+		ConstructorName: "New" + u.Camelize(r.ResourceKey) + "Resource",
+		ModelName:       modelName,
+		SDKValue:        "obj",
+		ExpanderName:    helpergen.ExpanderFuncName(r.SDKType),
+		FlattenerName:   helpergen.FlattenerFuncName(r.SDKType),
+		ParseIDFuncName: "parse" + u.Camelize(r.ResourceKey) + "Id",
+		BuildIDFuncName: "build" + u.Camelize(r.ResourceKey) + "Id",
+		IDTestName:      "Test" + u.Camelize(r.ResourceKey) + "Id",
+		ID:              id,
+		Create:          id.clientCall(r.CreateCall),
+		Read:            id.clientCall(r.ReadCall),
+		Delete:          id.clientCall(r.DeleteCall),
+	}
+	if r.UpdateCall != "" {
+		rc.Update = id.clientCall(r.UpdateCall)
+	}
+	if r.Backend == schemagen.Framework {
+		// Models convert from/to the value of the SDK struct
+		objCode := "obj"
+		if reflect.TypeOf(r.SDKType).Kind() == reflect.Ptr {
+			objCode, rc.SDKValue = "&obj", "*obj"
+		}
+		rc.Create = id.frameworkClientCall(r.CreateCall, objCode)
+		if r.UpdateCall != "" {
+			rc.Update = id.clientCallWithSuffix(r.UpdateCall, "", objCode)
+		}
+	}
+	if r.Backend != schemagen.Framework {
+		for v := 0; v < r.SchemaVersion(); v++ {
+			rc.StateUpgraders = append(rc.StateUpgraders, v)
+		}
+	}
+
+	return rc, nil
+}
+
+type resourceCode struct {
+	*Resource
+
+	FuncName        string
+	TypeName        string
+	ConstructorName string
+	ModelName       string
+	// SDK struct passed to the model's fromSDK, e.g. *obj
+	SDKValue        string
+	ExpanderName    string
+	FlattenerName   string
+	ParseIDFuncName string
+	BuildIDFuncName string
+	IDTestName      string
+
+	// Versions of previous schemas, see GenerateStateUpgraderCode
+	StateUpgraders []int
+
+	ID     *idFormat
+	Create *clientCall
+	Read   *clientCall
+	Update *clientCall
+	Delete *clientCall
+}
+
+type clientCall struct {
+	Code string
+	// e.g. "namespace, _" or empty if the ID doesn't need to be parsed
+	IDVars string
+	// Parts referred to by the call, these are read from attributes before Create
+	Parts []*idPart
+}
+
+type idFormat struct {
+	Format    string
+	Separator string
+	Example   string
+	Parts     []*idPart
+}
+
+type idPart struct {
+	Path    string
+	Name    string
+	VarName string
+}
+
+// e.g. path.Root("metadata").AtListIndex(0).AtName("name")
+func (p *idPart) FrameworkPath() string {
+	segments := strings.Split(p.Path, ".")
+	code := fmt.Sprintf("path.Root(%q)", segments[0])
+	for _, s := range segments[1:] {
+		if i, err := strconv.Atoi(s); err == nil {
+			code += fmt.Sprintf(".AtListIndex(%d)", i)
+			continue
+		}
+		code += fmt.Sprintf(".AtName(%q)", s)
+	}
+	return code
+}
+
+// e.g. ConfigMapModel, unless the name is given
+func frameworkModelName(name string, sdkType interface{}) string {
+	if name == "" {
+		return helpergen.ModelName(sdkType)
+	}
+	return strings.TrimSuffix(name, "Model") + "Model"
+}
+
+func parseIDFormat(format string) (*idFormat, error) {
+	f, err := u.ParseIDFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	id := &idFormat{
+		Format:    f.Format,
+		Separator: f.Separator,
+		Example:   f.Example(),
+	}
+	for _, p := range f.Parts {
+		varName := u.LowerFirst(u.Camelize(p.Name))
+		if reservedVarNames[varName] {
+			varName += "Part"
+		}
+		id.Parts = append(id.Parts, &idPart{
+			Path:    p.Path,
+			Name:    p.Name,
+			VarName: varName,
+		})
+	}
+
+	return id, nil
+}
+
+// Variables used in the generated code which ID parts must not shadow
+var reservedVarNames = map[string]bool{
+	"id": true, "parts": true, "d": true, "meta": true, "conn": true,
+	"obj": true, "out": true, "err": true, "k": true, "v": true, "cfg": true,
+	"ctx": true, "req": true, "resp": true, "r": true, "ok": true, "plan": true, "state": true,
+}
+
+func (id *idFormat) clientCall(call string) *clientCall {
+	return id.clientCallWithSuffix(call, "", "obj")
+}
+
+// Framework reads ID parts (before Create) as types.String, e.g. name.ValueString()
+func (id *idFormat) frameworkClientCall(call, objCode string) *clientCall {
+	return id.clientCallWithSuffix(call, ".ValueString()", objCode)
+}
+
+func (id *idFormat) clientCallWithSuffix(call, suffix, objCode string) *clientCall {
+	cc := &clientCall{}
+	vars := make([]string, len(id.Parts))
+	replacements := []string{"{obj}", objCode}
+	for i, p := range id.Parts {
+		placeholder := "{" + p.Path + "}"
+		vars[i] = "_"
+		if strings.Contains(call, placeholder) {
+			vars[i] = p.VarName
+			cc.Parts = append(cc.Parts, p)
+		}
+		replacements = append(replacements, placeholder, p.VarName+suffix)
+	}
+
+	cc.Code = strings.NewReplacer(replacements...).Replace(call)
+	if len(cc.Parts) > 0 {
+		cc.IDVars = strings.Join(vars, ", ")
+	}
+	return cc
+}
+
+func (id *idFormat) VarNames() string {
+	names := make([]string, len(id.Parts))
+	for i, p := range id.Parts {
+		names[i] = p.VarName
+	}
+	return strings.Join(names, ", ")
+}
+
+func (id *idFormat) BlankVarNames() string {
+	return strings.Repeat("_, ", len(id.Parts)-1) + "_"
+}
+
+func (id *idFormat) ReturnTypes() string {
+	return strings.Repeat("string, ", len(id.Parts)) + "error"
+}
+
+func (id *idFormat) EmptyReturnValues() string {
+	return strings.Repeat(`"", `, len(id.Parts))
+}
+
+func (id *idFormat) SplitValues() string {
+	values := make([]string, len(id.Parts))
+	for i := range id.Parts {
+		values[i] = fmt.Sprintf("parts[%d]", i)
+	}
+	return strings.Join(values, ", ")
+}
+
+func (id *idFormat) JoinedVarNames() string {
+	names := make([]string, len(id.Parts))
+	for i, p := range id.Parts {
+		names[i] = p.VarName
+	}
+	return strings.Join(names, fmt.Sprintf(" + %q + ", id.Separator))
+}
+
+func (id *idFormat) AttributeValues() string {
+	values := make([]string, len(id.Parts))
+	for i, p := range id.Parts {
+		values[i] = fmt.Sprintf("d.Get(%q).(string)", p.Path)
+	}
+	return strings.Join(values, ", ")
+}
+
+// e.g. namespace.ValueString(), name.ValueString()
+func (id *idFormat) StringValues() string {
+	values := make([]string, len(id.Parts))
+	for i, p := range id.Parts {
+		values[i] = p.VarName + ".ValueString()"
+	}
+	return strings.Join(values, ", ")
+}
+
+// ID parts referred to by CreateCall, the ID isn't known yet
+var createIDPartsCode = `{{- range .Create.Parts}}
+	{{.VarName}} := d.Get({{printf "%q" .Path}}).(string)
+{{- end}}
+`
+
+var resourceTemplate = template.Must(template.New("resource").Parse(`package {{.PkgName}}
+
+import (
+{{- if gt (len .ID.Parts) 1}}
+	"fmt"
+{{- end}}
+	"log"
+{{- if gt (len .ID.Parts) 1}}
+	"strings"
+{{- end}}
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func {{.FuncName}}() *schema.Resource {
+	return &schema.Resource{
+		Create: {{.FuncName}}Create,
+		Read:   {{.FuncName}}Read,
+		Exists: {{.FuncName}}Exists,
+{{- if .Update}}
+		Update: {{.FuncName}}Update,
+{{- end}}
+		Delete: {{.FuncName}}Delete,
+		Importer: &schema.ResourceImporter{
+			State: {{.FuncName}}ImportState,
+		},
+
+		Schema: {{.SchemaVarName}},
+` + stateUpgradersCode + `	}
+}
+
+func {{.FuncName}}Create(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.({{.ClientType}})
+` + createIDPartsCode + `
+	obj := {{.ExpanderName}}([]interface{}{ {{- .FuncName}}Config(d)})
+	log.Printf("[INFO] Creating new {{.ResourceKey}}: %#v", obj)
+	out, err := {{.Create.Code}}
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Submitted new {{.ResourceKey}}: %#v", out)
+
+	d.SetId({{.BuildIDFuncName}}({{.ID.AttributeValues}}))
+
+	return {{.FuncName}}Read(d, meta)
+}
+
+func {{.FuncName}}Read(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.({{.ClientType}})
+{{- if .Read.IDVars}}
+
+	{{.Read.IDVars}}, err := {{.ParseIDFuncName}}(d.Id())
+	if err != nil {
+		return err
+	}
+{{- end}}
+
+	log.Printf("[INFO] Reading {{.ResourceKey}} %s", d.Id())
+	obj, err := {{.Read.Code}}
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Received {{.ResourceKey}}: %#v", obj)
+
+	for k, v := range {{.FlattenerName}}(obj)[0].(map[string]interface{}) {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+{{- if .Update}}
+
+func {{.FuncName}}Update(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.({{.ClientType}})
+{{- if .Update.IDVars}}
+
+	{{.Update.IDVars}}, err := {{.ParseIDFuncName}}(d.Id())
+	if err != nil {
+		return err
+	}
+{{- end}}
+
+	obj := {{.ExpanderName}}([]interface{}{ {{- .FuncName}}Config(d)})
+	log.Printf("[INFO] Updating {{.ResourceKey}} %s: %#v", d.Id(), obj)
+	out, err := {{.Update.Code}}
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Submitted updated {{.ResourceKey}}: %#v", out)
+
+	return {{.FuncName}}Read(d, meta)
+}
+{{- end}}
+
+func {{.FuncName}}Delete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.({{.ClientType}})
+{{- if .Delete.IDVars}}
+
+	{{.Delete.IDVars}}, err := {{.ParseIDFuncName}}(d.Id())
+	if err != nil {
+		return err
+	}
+{{- end}}
+
+	log.Printf("[INFO] Deleting {{.ResourceKey}}: %s", d.Id())
+	if err := {{.Delete.Code}}; err != nil {
+		return err
+	}
+	log.Printf("[INFO] {{.ResourceKey}} %s deleted", d.Id())
+
+	d.SetId("")
+	return nil
+}
+
+func {{.FuncName}}Exists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	conn := meta.({{.ClientType}})
+{{- if .Read.IDVars}}
+
+	{{.Read.IDVars}}, err := {{.ParseIDFuncName}}(d.Id())
+	if err != nil {
+		return false, err
+	}
+{{- end}}
+
+	log.Printf("[INFO] Checking {{.ResourceKey}} %s", d.Id())
+	_, err {{if .Read.IDVars}}={{else}}:={{end}} {{.Read.Code}}
+	if err != nil {
+{{- if .IsNotFoundFunc}}
+		if {{.IsNotFoundFunc}}(err) {
+			return false, nil
+		}
+{{- end}}
+		log.Printf("[DEBUG] Received error: %#v", err)
+	}
+	return err == nil, err
+}
+
+func {{.FuncName}}ImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if {{.ID.BlankVarNames}}, err := {{.ParseIDFuncName}}(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+` + configFuncCode + idFuncsCode))
+
+// Shared by templates of SDK backends
+const configFuncCode = `func {{.FuncName}}Config(d *schema.ResourceData) map[string]interface{} {
+	cfg := make(map[string]interface{}, len({{.SchemaVarName}}))
+	for k := range {{.SchemaVarName}} {
+		v := d.Get(k)
+		if s, ok := v.(*schema.Set); ok {
+			v = s.List()
+		}
+		cfg[k] = v
+	}
+	return cfg
+}
+
+`
+
+// Shared by templates of all backends
+const idFuncsCode = `func {{.BuildIDFuncName}}({{.ID.VarNames}} string) string {
+	return {{.ID.JoinedVarNames}}
+}
+
+func {{.ParseIDFuncName}}(id string) ({{.ID.ReturnTypes}}) {
+{{- if gt (len .ID.Parts) 1}}
+	parts := strings.SplitN(id, {{printf "%q" .ID.Separator}}, {{len .ID.Parts}})
+	if len(parts) != {{len .ID.Parts}} {
+		return {{.ID.EmptyReturnValues}}fmt.Errorf("Unexpected ID format (%q), expected %q", id, {{printf "%q" .ID.Format}})
+	}
+	return {{.ID.SplitValues}}, nil
+{{- else}}
+	return id, nil
+{{- end}}
+}
+`
+
+var idTestTemplate = template.Must(template.New("id-test").Parse(`package {{.PkgName}}
+
+import (
+	"testing"
+)
+
+func {{.IDTestName}}_roundTrip(t *testing.T) {
+	id := {{.BuildIDFuncName}}({{range $i, $p := .ID.Parts}}{{if $i}}, {{end}}{{printf "%q" $p.Name}}{{end}})
+	if id != {{printf "%q" .ID.Example}} {
+		t.Fatalf("Expected ID %q, given: %q", {{printf "%q" .ID.Example}}, id)
+	}
+
+	{{.ID.VarNames}}, err := {{.ParseIDFuncName}}(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+{{- range .ID.Parts}}
+	if {{.VarName}} != {{printf "%q" .Name}} {
+		t.Fatalf("Expected {{.Name}} %q, given: %q", {{printf "%q" .Name}}, {{.VarName}})
+	}
+{{- end}}
+}
+{{- if gt (len .ID.Parts) 1}}
+
+func {{.IDTestName}}_invalid(t *testing.T) {
+	{{.ID.BlankVarNames}}, err := {{.ParseIDFuncName}}({{printf "%q" (index .ID.Parts 0).Name}})
+	if err == nil {
+		t.Fatal("Expected error for ID with missing parts")
+	}
+}
+{{- end}}
+`))
@@ -0,0 +1,708 @@
+package resourcegen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+type ConfigMap struct {
+	Name      string
+	Namespace string
+}
+
+func TestGenerateResourceCode_basic(t *testing.T) {
+	r := &Resource{
+		PkgName:        "kubernetes",
+		ResourceKey:    "kubernetes_config_map",
+		SDKType:        ConfigMap{},
+		SchemaVarName:  "configMapSchema",
+		ClientType:     "*kubernetes.Clientset",
+		CreateCall:     "conn.CoreV1().ConfigMaps({obj}.Namespace).Create(&{obj})",
+		ReadCall:       "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Get({metadata.0.name})",
+		UpdateCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Update(&{obj})",
+		DeleteCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Delete({metadata.0.name}, nil)",
+		IsNotFoundFunc: "errors.IsNotFound",
+		IDFormat:       "{metadata.0.namespace}/{metadata.0.name}",
+	}
+
+	if r.Filename() != "resource_kubernetes_config_map.go" {
+		t.Fatalf("Unexpected filename: %q", r.Filename())
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := r.GenerateResourceCode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	if output != resource_basic_output {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", resource_basic_output, output)
+	}
+}
+
+func TestGenerateIDTestCode_basic(t *testing.T) {
+	r := &Resource{
+		PkgName:     "cattle",
+		ResourceKey: "cattle_cow",
+		SDKType:     ConfigMap{},
+		IDFormat:    "{herd}:{id}",
+	}
+
+	if r.IDTestFilename() != "resource_cattle_cow_id_test.go" {
+		t.Fatalf("Unexpected filename: %q", r.IDTestFilename())
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := r.GenerateIDTestCode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	if output != id_test_basic_output {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", id_test_basic_output, output)
+	}
+}
+
+func TestGenerateResourceCode_invalidIDFormat(t *testing.T) {
+	formats := []string{
+		"name",
+		"id-{name}",
+		"{namespace}{name}",
+		"{project}/{region}:{name}",
+	}
+	for _, format := range formats {
+		r := &Resource{
+			PkgName:     "cattle",
+			ResourceKey: "cattle_cow",
+			SDKType:     ConfigMap{},
+			IDFormat:    format,
+		}
+		err := r.GenerateResourceCode(bytes.NewBuffer([]byte{}))
+		if err == nil {
+			t.Fatalf("Expected error for ID format %q", format)
+		}
+	}
+}
+
+var resource_basic_output = `package kubernetes
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceKubernetesConfigMap() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKubernetesConfigMapCreate,
+		Read:   resourceKubernetesConfigMapRead,
+		Exists: resourceKubernetesConfigMapExists,
+		Update: resourceKubernetesConfigMapUpdate,
+		Delete: resourceKubernetesConfigMapDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceKubernetesConfigMapImportState,
+		},
+
+		Schema: configMapSchema,
+	}
+}
+
+func resourceKubernetesConfigMapCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*kubernetes.Clientset)
+
+	obj := expandConfigMap([]interface{}{resourceKubernetesConfigMapConfig(d)})
+	log.Printf("[INFO] Creating new kubernetes_config_map: %#v", obj)
+	out, err := conn.CoreV1().ConfigMaps(obj.Namespace).Create(&obj)
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Submitted new kubernetes_config_map: %#v", out)
+
+	d.SetId(buildKubernetesConfigMapId(d.Get("metadata.0.namespace").(string), d.Get("metadata.0.name").(string)))
+
+	return resourceKubernetesConfigMapRead(d, meta)
+}
+
+func resourceKubernetesConfigMapRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*kubernetes.Clientset)
+
+	namespace, name, err := parseKubernetesConfigMapId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Reading kubernetes_config_map %s", d.Id())
+	obj, err := conn.CoreV1().ConfigMaps(namespace).Get(name)
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Received kubernetes_config_map: %#v", obj)
+
+	for k, v := range flattenConfigMap(obj)[0].(map[string]interface{}) {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resourceKubernetesConfigMapUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*kubernetes.Clientset)
+
+	namespace, _, err := parseKubernetesConfigMapId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	obj := expandConfigMap([]interface{}{resourceKubernetesConfigMapConfig(d)})
+	log.Printf("[INFO] Updating kubernetes_config_map %s: %#v", d.Id(), obj)
+	out, err := conn.CoreV1().ConfigMaps(namespace).Update(&obj)
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] Submitted updated kubernetes_config_map: %#v", out)
+
+	return resourceKubernetesConfigMapRead(d, meta)
+}
+
+func resourceKubernetesConfigMapDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*kubernetes.Clientset)
+
+	namespace, name, err := parseKubernetesConfigMapId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Deleting kubernetes_config_map: %s", d.Id())
+	if err := conn.CoreV1().ConfigMaps(namespace).Delete(name, nil); err != nil {
+		return err
+	}
+	log.Printf("[INFO] kubernetes_config_map %s deleted", d.Id())
+
+	d.SetId("")
+	return nil
+}
+
+func resourceKubernetesConfigMapExists(d *schema.ResourceData, meta interface{}) (bool, error) {
+	conn := meta.(*kubernetes.Clientset)
+
+	namespace, name, err := parseKubernetesConfigMapId(d.Id())
+	if err != nil {
+		return false, err
+	}
+
+	log.Printf("[INFO] Checking kubernetes_config_map %s", d.Id())
+	_, err = conn.CoreV1().ConfigMaps(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		log.Printf("[DEBUG] Received error: %#v", err)
+	}
+	return err == nil, err
+}
+
+func resourceKubernetesConfigMapImportState(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseKubernetesConfigMapId(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceKubernetesConfigMapConfig(d *schema.ResourceData) map[string]interface{} {
+	cfg := make(map[string]interface{}, len(configMapSchema))
+	for k := range configMapSchema {
+		v := d.Get(k)
+		if s, ok := v.(*schema.Set); ok {
+			v = s.List()
+		}
+		cfg[k] = v
+	}
+	return cfg
+}
+
+func buildKubernetesConfigMapId(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func parseKubernetesConfigMapId(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("Unexpected ID format (%q), expected %q", id, "{metadata.0.namespace}/{metadata.0.name}")
+	}
+	return parts[0], parts[1], nil
+}
+`
+
+var id_test_basic_output = `package cattle
+
+import (
+	"testing"
+)
+
+func TestCattleCowId_roundTrip(t *testing.T) {
+	id := buildCattleCowId("herd", "id")
+	if id != "herd:id" {
+		t.Fatalf("Expected ID %q, given: %q", "herd:id", id)
+	}
+
+	herd, idPart, err := parseCattleCowId(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if herd != "herd" {
+		t.Fatalf("Expected herd %q, given: %q", "herd", herd)
+	}
+	if idPart != "id" {
+		t.Fatalf("Expected id %q, given: %q", "id", idPart)
+	}
+}
+
+func TestCattleCowId_invalid(t *testing.T) {
+	_, _, err := parseCattleCowId("herd")
+	if err == nil {
+		t.Fatal("Expected error for ID with missing parts")
+	}
+}
+`
+
+func TestGenerateResourceCode_sdkv2(t *testing.T) {
+	r := &Resource{
+		PkgName:        "kubernetes",
+		ResourceKey:    "kubernetes_config_map",
+		Backend:        schemagen.SDKv2,
+		SDKType:        ConfigMap{},
+		SchemaVarName:  "configMapSchema",
+		ClientType:     "*kubernetes.Clientset",
+		CreateCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Create(&{obj})",
+		ReadCall:       "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Get({metadata.0.name})",
+		UpdateCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Update(&{obj})",
+		DeleteCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Delete({metadata.0.name}, nil)",
+		IsNotFoundFunc: "errors.IsNotFound",
+		IDFormat:       "{metadata.0.namespace}/{metadata.0.name}",
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := r.GenerateResourceCode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	if output != resource_sdkv2_output {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", resource_sdkv2_output, output)
+	}
+}
+
+var resource_sdkv2_output = `package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceKubernetesConfigMap() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceKubernetesConfigMapCreate,
+		ReadContext:   resourceKubernetesConfigMapRead,
+		UpdateContext: resourceKubernetesConfigMapUpdate,
+		DeleteContext: resourceKubernetesConfigMapDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKubernetesConfigMapImportState,
+		},
+
+		Schema: configMapSchema,
+	}
+}
+
+func resourceKubernetesConfigMapCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*kubernetes.Clientset)
+	namespace := d.Get("metadata.0.namespace").(string)
+
+	obj := expandConfigMap([]interface{}{resourceKubernetesConfigMapConfig(d)})
+	log.Printf("[INFO] Creating new kubernetes_config_map: %#v", obj)
+	out, err := conn.CoreV1().ConfigMaps(namespace).Create(&obj)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Submitted new kubernetes_config_map: %#v", out)
+
+	d.SetId(buildKubernetesConfigMapId(d.Get("metadata.0.namespace").(string), d.Get("metadata.0.name").(string)))
+
+	return resourceKubernetesConfigMapRead(ctx, d, meta)
+}
+
+func resourceKubernetesConfigMapRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*kubernetes.Clientset)
+
+	namespace, name, err := parseKubernetesConfigMapId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Reading kubernetes_config_map %s", d.Id())
+	obj, err := conn.CoreV1().ConfigMaps(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Printf("[WARN] kubernetes_config_map %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Received kubernetes_config_map: %#v", obj)
+
+	for k, v := range flattenConfigMap(obj)[0].(map[string]interface{}) {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceKubernetesConfigMapUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*kubernetes.Clientset)
+
+	namespace, _, err := parseKubernetesConfigMapId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	obj := expandConfigMap([]interface{}{resourceKubernetesConfigMapConfig(d)})
+	log.Printf("[INFO] Updating kubernetes_config_map %s: %#v", d.Id(), obj)
+	out, err := conn.CoreV1().ConfigMaps(namespace).Update(&obj)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Submitted updated kubernetes_config_map: %#v", out)
+
+	return resourceKubernetesConfigMapRead(ctx, d, meta)
+}
+
+func resourceKubernetesConfigMapDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.(*kubernetes.Clientset)
+
+	namespace, name, err := parseKubernetesConfigMapId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Deleting kubernetes_config_map: %s", d.Id())
+	if err := conn.CoreV1().ConfigMaps(namespace).Delete(name, nil); err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] kubernetes_config_map %s deleted", d.Id())
+
+	d.SetId("")
+	return nil
+}
+
+func resourceKubernetesConfigMapImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseKubernetesConfigMapId(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceKubernetesConfigMapConfig(d *schema.ResourceData) map[string]interface{} {
+	cfg := make(map[string]interface{}, len(configMapSchema))
+	for k := range configMapSchema {
+		v := d.Get(k)
+		if s, ok := v.(*schema.Set); ok {
+			v = s.List()
+		}
+		cfg[k] = v
+	}
+	return cfg
+}
+
+func buildKubernetesConfigMapId(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func parseKubernetesConfigMapId(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("Unexpected ID format (%q), expected %q", id, "{metadata.0.namespace}/{metadata.0.name}")
+	}
+	return parts[0], parts[1], nil
+}
+`
+
+func TestGenerateResourceCode_framework(t *testing.T) {
+	r := &Resource{
+		PkgName:        "kubernetes",
+		ResourceKey:    "kubernetes_config_map",
+		Backend:        schemagen.Framework,
+		SDKType:        ConfigMap{},
+		SchemaVarName:  "configMapSchema",
+		ClientType:     "*kubernetes.Clientset",
+		CreateCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Create(&{obj})",
+		ReadCall:       "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Get({metadata.0.name})",
+		UpdateCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Update(&{obj})",
+		DeleteCall:     "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Delete({metadata.0.name}, nil)",
+		IsNotFoundFunc: "errors.IsNotFound",
+		IDFormat:       "{metadata.0.namespace}/{metadata.0.name}",
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := r.GenerateResourceCode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	if output != resource_framework_output {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", resource_framework_output, output)
+	}
+}
+
+// Models convert from/to the SDK struct's value, so pointers are taken & dereferenced
+func TestGenerateResourceCode_frameworkPtrType(t *testing.T) {
+	r := &Resource{
+		PkgName:       "kubernetes",
+		ResourceKey:   "kubernetes_config_map",
+		Backend:       schemagen.Framework,
+		SDKType:       &ConfigMap{},
+		SchemaVarName: "configMapSchema",
+		ClientType:    "*kubernetes.Clientset",
+		CreateCall:    "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Create({obj})",
+		ReadCall:      "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Get({metadata.0.name})",
+		UpdateCall:    "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Update({obj})",
+		DeleteCall:    "conn.CoreV1().ConfigMaps({metadata.0.namespace}).Delete({metadata.0.name}, nil)",
+		IDFormat:      "{metadata.0.namespace}/{metadata.0.name}",
+	}
+
+	rc, err := r.resourceCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedCreate := "conn.CoreV1().ConfigMaps(namespace.ValueString()).Create(&obj)"
+	if rc.Create.Code != expectedCreate {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedCreate, rc.Create.Code)
+	}
+	expectedUpdate := "conn.CoreV1().ConfigMaps(namespace).Update(&obj)"
+	if rc.Update.Code != expectedUpdate {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedUpdate, rc.Update.Code)
+	}
+	if rc.SDKValue != "*obj" {
+		t.Fatalf("Expected: *obj\n\nGiven: %s\n", rc.SDKValue)
+	}
+	if rc.ModelName != "ConfigMapModel" {
+		t.Fatalf("Expected: ConfigMapModel\n\nGiven: %s\n", rc.ModelName)
+	}
+}
+
+var resource_framework_output = `package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &kubernetesConfigMapResource{}
+	_ resource.ResourceWithConfigure   = &kubernetesConfigMapResource{}
+	_ resource.ResourceWithImportState = &kubernetesConfigMapResource{}
+)
+
+func NewKubernetesConfigMapResource() resource.Resource {
+	return &kubernetesConfigMapResource{}
+}
+
+type kubernetesConfigMapResource struct {
+	conn *kubernetes.Clientset
+}
+
+type kubernetesConfigMapResourceModel struct {
+	Id types.String ` + "`" + `tfsdk:"id"` + "`" + `
+	ConfigMapModel
+}
+
+func (r *kubernetesConfigMapResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = "kubernetes_config_map"
+}
+
+func (r *kubernetesConfigMapResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = configMapSchema
+}
+
+func (r *kubernetesConfigMapResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	conn, ok := req.ProviderData.(*kubernetes.Clientset)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data",
+			fmt.Sprintf("Expected *kubernetes.Clientset, given: %T", req.ProviderData))
+		return
+	}
+	r.conn = conn
+}
+
+func (r *kubernetesConfigMapResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	conn := r.conn
+
+	var plan kubernetesConfigMapResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	var namespace types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("metadata").AtListIndex(0).AtName("namespace"), &namespace)...)
+	var name types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("metadata").AtListIndex(0).AtName("name"), &name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	obj := plan.toSDK()
+	log.Printf("[INFO] Creating new kubernetes_config_map: %#v", obj)
+	out, err := conn.CoreV1().ConfigMaps(namespace.ValueString()).Create(&obj)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating kubernetes_config_map", err.Error())
+		return
+	}
+	log.Printf("[INFO] Submitted new kubernetes_config_map: %#v", out)
+
+	state, err := r.read(ctx, buildKubernetesConfigMapId(namespace.ValueString(), name.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading kubernetes_config_map", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *kubernetesConfigMapResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var id string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state, err := r.read(ctx, id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Printf("[WARN] kubernetes_config_map %s not found, removing from state", id)
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading kubernetes_config_map", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *kubernetesConfigMapResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	conn := r.conn
+
+	var plan kubernetesConfigMapResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	var id string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace, _, err := parseKubernetesConfigMapId(id)
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating kubernetes_config_map", err.Error())
+		return
+	}
+
+	obj := plan.toSDK()
+	log.Printf("[INFO] Updating kubernetes_config_map %s: %#v", id, obj)
+	out, err := conn.CoreV1().ConfigMaps(namespace).Update(&obj)
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating kubernetes_config_map", err.Error())
+		return
+	}
+	log.Printf("[INFO] Submitted updated kubernetes_config_map: %#v", out)
+
+	state, err := r.read(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading kubernetes_config_map", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *kubernetesConfigMapResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	conn := r.conn
+
+	var id string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace, name, err := parseKubernetesConfigMapId(id)
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting kubernetes_config_map", err.Error())
+		return
+	}
+
+	log.Printf("[INFO] Deleting kubernetes_config_map: %s", id)
+	if err := conn.CoreV1().ConfigMaps(namespace).Delete(name, nil); err != nil {
+		resp.Diagnostics.AddError("Error deleting kubernetes_config_map", err.Error())
+		return
+	}
+	log.Printf("[INFO] kubernetes_config_map %s deleted", id)
+}
+
+func (r *kubernetesConfigMapResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if _, _, err := parseKubernetesConfigMapId(req.ID); err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *kubernetesConfigMapResource) read(ctx context.Context, id string) (*kubernetesConfigMapResourceModel, error) {
+	conn := r.conn
+
+	namespace, name, err := parseKubernetesConfigMapId(id)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[INFO] Reading kubernetes_config_map %s", id)
+	obj, err := conn.CoreV1().ConfigMaps(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[INFO] Received kubernetes_config_map: %#v", obj)
+
+	state := &kubernetesConfigMapResourceModel{Id: types.StringValue(id)}
+	state.fromSDK(obj)
+	return state, nil
+}
+
+func buildKubernetesConfigMapId(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func parseKubernetesConfigMapId(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("Unexpected ID format (%q), expected %q", id, "{metadata.0.namespace}/{metadata.0.name}")
+	}
+	return parts[0], parts[1], nil
+}
+`
@@ -0,0 +1,181 @@
+package resourcegen
+
+import (
+	"text/template"
+)
+
+var sdkv2ResourceTemplate = template.Must(template.New("resource-sdkv2").Parse(`package {{.PkgName}}
+
+import (
+	"context"
+{{- if gt (len .ID.Parts) 1}}
+	"fmt"
+{{- end}}
+	"log"
+{{- if gt (len .ID.Parts) 1}}
+	"strings"
+{{- end}}
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func {{.FuncName}}() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: {{.FuncName}}Create,
+		ReadContext:   {{.FuncName}}Read,
+{{- if .Update}}
+		UpdateContext: {{.FuncName}}Update,
+{{- end}}
+		DeleteContext: {{.FuncName}}Delete,
+		Importer: &schema.ResourceImporter{
+			StateContext: {{.FuncName}}ImportState,
+		},
+
+		Schema: {{.SchemaVarName}},
+` + stateUpgradersCode + `	}
+}
+
+func {{.FuncName}}Create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.({{.ClientType}})
+` + createIDPartsCode + `
+	obj := {{.ExpanderName}}([]interface{}{ {{- .FuncName}}Config(d)})
+	log.Printf("[INFO] Creating new {{.ResourceKey}}: %#v", obj)
+	out, err := {{.Create.Code}}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Submitted new {{.ResourceKey}}: %#v", out)
+
+	d.SetId({{.BuildIDFuncName}}({{.ID.AttributeValues}}))
+
+	return {{.FuncName}}Read(ctx, d, meta)
+}
+
+func {{.FuncName}}Read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.({{.ClientType}})
+{{- if .Read.IDVars}}
+
+	{{.Read.IDVars}}, err := {{.ParseIDFuncName}}(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+{{- end}}
+
+	log.Printf("[INFO] Reading {{.ResourceKey}} %s", d.Id())
+	obj, err := {{.Read.Code}}
+	if err != nil {
+{{- if .IsNotFoundFunc}}
+		if {{.IsNotFoundFunc}}(err) {
+			log.Printf("[WARN] {{.ResourceKey}} %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+{{- end}}
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Received {{.ResourceKey}}: %#v", obj)
+
+	for k, v := range {{.FlattenerName}}(obj)[0].(map[string]interface{}) {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+{{- if .Update}}
+
+func {{.FuncName}}Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.({{.ClientType}})
+{{- if .Update.IDVars}}
+
+	{{.Update.IDVars}}, err := {{.ParseIDFuncName}}(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+{{- end}}
+
+	obj := {{.ExpanderName}}([]interface{}{ {{- .FuncName}}Config(d)})
+	log.Printf("[INFO] Updating {{.ResourceKey}} %s: %#v", d.Id(), obj)
+	out, err := {{.Update.Code}}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Submitted updated {{.ResourceKey}}: %#v", out)
+
+	return {{.FuncName}}Read(ctx, d, meta)
+}
+{{- end}}
+
+func {{.FuncName}}Delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.({{.ClientType}})
+{{- if .Delete.IDVars}}
+
+	{{.Delete.IDVars}}, err := {{.ParseIDFuncName}}(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+{{- end}}
+
+	log.Printf("[INFO] Deleting {{.ResourceKey}}: %s", d.Id())
+	if err := {{.Delete.Code}}; err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] {{.ResourceKey}} %s deleted", d.Id())
+
+	d.SetId("")
+	return nil
+}
+
+func {{.FuncName}}ImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if {{.ID.BlankVarNames}}, err := {{.ParseIDFuncName}}(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+` + configFuncCode + idFuncsCode))
+
+var sdkv2DataSourceTemplate = template.Must(template.New("data-source-sdkv2").Parse(`package {{.PkgName}}
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func {{.FuncName}}() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: {{.FuncName}}Read,
+
+		Schema: {{.SchemaVarName}},
+	}
+}
+
+func {{.FuncName}}Read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn := meta.({{.ClientType}})
+{{range .ID.Parts}}
+	{{.VarName}} := d.Get({{printf "%q" .Path}}).(string)
+{{- end}}
+	id := {{.ID.JoinedVarNames}}
+
+	log.Printf("[INFO] Reading {{.DataSourceKey}} %s", id)
+	obj, err := {{.Read.Code}}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Received {{.DataSourceKey}}: %#v", obj)
+
+	for k, v := range {{.FlattenerName}}(obj)[0].(map[string]interface{}) {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	d.SetId(id)
+
+	return nil
+}
+`))
@@ -0,0 +1,308 @@
+package resourcegen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/schemadiff"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+// SchemaVersion of the generated schema, i.e. the previous version,
+// bumped if the state written by the previous release can't be decoded anymore
+func (r *Resource) SchemaVersion() int {
+	if r.PreviousSchema == nil {
+		return 0
+	}
+	if len(r.stateChanges()) > 0 {
+		return r.PreviousSchema.SchemaVersion + 1
+	}
+	return r.PreviousSchema.SchemaVersion
+}
+
+func (r *Resource) StateUpgraderFilename() string {
+	return fmt.Sprintf("resource_%s_state_upgrade_v%d.go", r.ResourceKey, r.PreviousSchema.SchemaVersion)
+}
+
+// GenerateStateUpgraderCode generates the previous schema (decoding the old state)
+// and a stub of the upgrade function, which renames, removes or converts top-level fields
+// and leaves TODOs for nested ones. Upgraders of older versions are expected
+// to be kept from previous releases, as these are referenced by the resource.
+func (r *Resource) GenerateStateUpgraderCode(wr io.Writer) error {
+	if r.Backend == schemagen.Framework {
+		return fmt.Errorf("State upgraders are only supported by SDK backends")
+	}
+	if r.PreviousSchema == nil {
+		return fmt.Errorf("No previous schema found")
+	}
+	changes := r.stateChanges()
+	if len(changes) == 0 {
+		return fmt.Errorf("No state upgrade needed, schema is compatible with version %d",
+			r.PreviousSchema.SchemaVersion)
+	}
+
+	rc, err := r.resourceCode()
+	if err != nil {
+		return err
+	}
+	prevSchema, err := schemagen.RenderFields(schemagen.FieldsFromResource(r.previousSchema()))
+	if err != nil {
+		return err
+	}
+
+	suc := &stateUpgraderCode{
+		resourceCode: rc,
+		Version:      r.PreviousSchema.SchemaVersion,
+		Schema:       prevSchema,
+		imports:      make(map[string]bool, 0),
+	}
+	for _, c := range changes {
+		suc.Statements = append(suc.Statements, suc.statement(c))
+	}
+
+	return stateUpgraderTemplate.Execute(wr, suc)
+}
+
+type stateUpgraderCode struct {
+	*resourceCode
+
+	Version    int
+	Schema     map[string]string
+	Statements []string
+
+	imports map[string]bool
+}
+
+func (suc *stateUpgraderCode) IsSDKv2() bool {
+	return suc.Backend == schemagen.SDKv2
+}
+
+func (suc *stateUpgraderCode) SchemaImportPath() string {
+	return suc.Backend.SchemaImportPath()
+}
+
+// Standard library packages used by statements
+func (suc *stateUpgraderCode) Imports() []string {
+	imports := make([]string, 0, len(suc.imports))
+	for path := range suc.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+// Change of the schema which affects the state
+type stateChange struct {
+	// Path in the previous schema, e.g. metadata.name
+	Path string
+	// Set for renamed fields
+	NewPath string
+
+	Kind    schemadiff.ChangeKind
+	Message string
+
+	Old, New *schema.Schema
+}
+
+// Removed fields & incompatible types, a removed field and an added one
+// of the same type are considered a rename, if these are the only ones of that type
+func (r *Resource) stateChanges() []*stateChange {
+	if r.PreviousSchema == nil {
+		return nil
+	}
+	previous := r.previousSchema()
+	current := (&schemagen.Block{Fields: r.SchemaFields}).SDKResource()
+
+	changes := make([]*stateChange, 0)
+	removed := make(map[string][]*stateChange, 0)
+	added := make(map[string][]*schemadiff.Change, 0)
+	for _, c := range schemadiff.Resources(previous, current) {
+		o := lookupSchema(previous, c.Path)
+		n := lookupSchema(current, c.Path)
+		switch c.Kind {
+		case schemadiff.FieldRemoved:
+			sc := &stateChange{Path: c.Path, Kind: c.Kind, Message: c.Message, Old: o}
+			if !strings.Contains(c.Path, ".") {
+				removed[stateType(o)] = append(removed[stateType(o)], sc)
+			}
+			changes = append(changes, sc)
+		case schemadiff.FieldAdded:
+			if !strings.Contains(c.Path, ".") {
+				added[stateType(n)] = append(added[stateType(n)], c)
+			}
+		case schemadiff.TypeChanged:
+			// Both are numbers in the state
+			if stateType(o) == stateType(n) {
+				continue
+			}
+			changes = append(changes, &stateChange{Path: c.Path, Kind: c.Kind, Message: c.Message, Old: o, New: n})
+		}
+	}
+
+	for typ, rc := range removed {
+		if len(rc) == 1 && len(added[typ]) == 1 {
+			rc[0].NewPath = added[typ][0].Path
+			rc[0].Message = "renamed to " + rc[0].NewPath
+		}
+	}
+	return changes
+}
+
+// Without the implicit id attribute (see schemajson.FromResource),
+// unless it's part of the generated schema
+func (r *Resource) previousSchema() *schema.Resource {
+	if _, ok := r.PreviousSchema.Schema["id"]; !ok {
+		return r.PreviousSchema
+	}
+	for _, f := range r.SchemaFields {
+		if f.Name == "id" {
+			return r.PreviousSchema
+		}
+	}
+
+	res := *r.PreviousSchema
+	res.Schema = make(map[string]*schema.Schema, len(r.PreviousSchema.Schema))
+	for name, s := range r.PreviousSchema.Schema {
+		if name != "id" {
+			res.Schema[name] = s
+		}
+	}
+	return &res
+}
+
+// e.g. raw state of a renamed field is moved under the new name
+func (suc *stateUpgraderCode) statement(c *stateChange) string {
+	if strings.Contains(c.Path, ".") {
+		return fmt.Sprintf("// TODO: %s: %s", c.Path, c.Message)
+	}
+
+	comment := fmt.Sprintf("// %s: %s\n\t", c.Path, c.Message)
+	switch {
+	case c.NewPath != "":
+		return comment + fmt.Sprintf(`if v, ok := rawState[%q]; ok {
+		rawState[%q] = v
+		delete(rawState, %q)
+	}`, c.Path, c.NewPath, c.Path)
+	case c.Kind == schemadiff.FieldRemoved:
+		return comment + fmt.Sprintf("delete(rawState, %q)", c.Path)
+	}
+
+	oldType, newType := stateType(c.Old), stateType(c.New)
+	switch {
+	case newType == "string" && isPrimitiveStateType(oldType):
+		suc.imports["fmt"] = true
+		return comment + fmt.Sprintf(`if v, ok := rawState[%q]; ok && v != nil {
+		rawState[%q] = fmt.Sprint(v)
+	}`, c.Path, c.Path)
+	case oldType == "string" && (newType == "number" || newType == "bool"):
+		suc.imports["fmt"] = true
+		suc.imports["strconv"] = true
+		parse := "strconv.ParseFloat(v, 64)"
+		if newType == "bool" {
+			parse = "strconv.ParseBool(v)"
+		}
+		return comment + fmt.Sprintf(`if v, ok := rawState[%q].(string); ok {
+		converted, err := %s
+		if err != nil {
+			return nil, fmt.Errorf("%s: %%s", err)
+		}
+		rawState[%q] = converted
+	}`, c.Path, parse, c.Path, c.Path)
+	}
+	return fmt.Sprintf("// TODO: %s: %s", c.Path, c.Message)
+}
+
+// Type of the value in the raw (JSON) state, e.g. number or list of string
+func stateType(s *schema.Schema) string {
+	var typ string
+	switch s.Type {
+	case schema.TypeInt, schema.TypeFloat:
+		typ = "number"
+	case schema.TypeString:
+		typ = "string"
+	case schema.TypeBool:
+		typ = "bool"
+	default:
+		typ = strings.ToLower(strings.TrimPrefix(s.Type.String(), "Type"))
+	}
+
+	switch elem := s.Elem.(type) {
+	case *schema.Schema:
+		return typ + " of " + stateType(elem)
+	case *schema.Resource:
+		return typ + " of block"
+	}
+	return typ
+}
+
+func isPrimitiveStateType(typ string) bool {
+	return typ == "number" || typ == "bool" || typ == "string"
+}
+
+// e.g. metadata.name, nil if not found
+func lookupSchema(res *schema.Resource, path string) *schema.Schema {
+	var s *schema.Schema
+	for _, name := range strings.Split(path, ".") {
+		if res == nil {
+			return nil
+		}
+		s = res.Schema[name]
+		if s == nil {
+			return nil
+		}
+		res, _ = s.Elem.(*schema.Resource)
+	}
+	return s
+}
+
+var stateUpgradersCode = `{{- if .StateUpgraders}}
+
+		SchemaVersion: {{.SchemaVersion}},
+		StateUpgraders: []schema.StateUpgrader{
+{{- range .StateUpgraders}}
+			{
+				Version: {{.}},
+				Type:    {{$.FuncName}}V{{.}}().CoreConfigSchema().ImpliedType(),
+				Upgrade: {{$.FuncName}}StateUpgradeV{{.}},
+			},
+{{- end}}
+		},
+{{- end}}
+`
+
+var stateUpgraderTemplate = template.Must(template.New("state-upgrader").Parse(`package {{.PkgName}}
+
+import (
+{{- if .IsSDKv2}}
+	"context"
+{{- end}}
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+
+	"{{.SchemaImportPath}}"
+)
+
+// Schema of version {{.Version}}, decoding the state written by previous releases
+func {{.FuncName}}V{{.Version}}() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+{{- range $name, $code := .Schema}}
+			{{printf "%q" $name}}: {{$code}},
+{{- end}}
+		},
+	}
+}
+
+func {{.FuncName}}StateUpgradeV{{.Version}}({{if .IsSDKv2}}ctx context.Context, {{end}}rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+{{- range .Statements}}
+	{{.}}
+{{- end}}
+
+	return rawState, nil
+}
+`))
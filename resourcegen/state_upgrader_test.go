@@ -0,0 +1,209 @@
+package resourcegen
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+func TestGenerateStateUpgraderCode(t *testing.T) {
+	type Owner struct {
+		Name string
+	}
+	type Cow struct {
+		FullName string
+		Age      string
+		Weight   float64
+		Owner    *Owner
+	}
+
+	g := &schemagen.SchemaGenerator{}
+	r := &Resource{
+		PkgName:       "cattle",
+		ResourceKey:   "cattle_cow",
+		Backend:       schemagen.SDKv2,
+		SDKType:       Cow{},
+		SchemaVarName: "cowSchema",
+		ClientType:    "*cattle.Client",
+		CreateCall:    "conn.Create({obj})",
+		ReadCall:      "conn.Get({name})",
+		DeleteCall:    "conn.Delete({name})",
+		IDFormat:      "{name}",
+		PreviousSchema: &schema.Resource{
+			SchemaVersion: 1,
+			Schema: map[string]*schema.Schema{
+				"name":   {Type: schema.TypeString, Required: true},
+				"age":    {Type: schema.TypeInt, Optional: true},
+				"weight": {Type: schema.TypeFloat, Required: true},
+				"barn":   {Type: schema.TypeInt, Optional: true},
+				"owner": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {Type: schema.TypeString, Required: true},
+							"farm": {Type: schema.TypeString, Optional: true},
+						},
+					},
+				},
+			},
+		},
+		SchemaFields: g.FieldsFromStruct(&Cow{}),
+	}
+
+	if r.SchemaVersion() != 2 {
+		t.Fatalf("Expected schema version 2, given: %d", r.SchemaVersion())
+	}
+	if r.StateUpgraderFilename() != "resource_cattle_cow_state_upgrade_v1.go" {
+		t.Fatalf("Unexpected filename: %q", r.StateUpgraderFilename())
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := r.GenerateStateUpgraderCode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+	if output != state_upgrader_sdkv2_output {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", state_upgrader_sdkv2_output, output)
+	}
+
+	rc, err := r.resourceCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []int{0, 1}; !reflect.DeepEqual(rc.StateUpgraders, expected) {
+		t.Fatalf("Expected state upgraders: %v, given: %v", expected, rc.StateUpgraders)
+	}
+	buf.Reset()
+	if err := r.GenerateResourceCode(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), state_upgraders_resource_snippet) {
+		t.Fatalf("Expected resource to contain:\n%s\nGiven: %s\n", state_upgraders_resource_snippet, buf.String())
+	}
+}
+
+func TestGenerateStateUpgraderCode_compatible(t *testing.T) {
+	type Cow struct {
+		Name string
+		Age  int
+	}
+
+	g := &schemagen.SchemaGenerator{}
+	r := &Resource{
+		PkgName:     "cattle",
+		ResourceKey: "cattle_cow",
+		SDKType:     Cow{},
+		IDFormat:    "{name}",
+		// Numbers are always TypeFloat & id is implicit in the JSON schema
+		PreviousSchema: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id":   {Type: schema.TypeString, Optional: true, Computed: true},
+				"name": {Type: schema.TypeString, Required: true},
+				"age":  {Type: schema.TypeFloat, Required: true},
+			},
+		},
+		SchemaFields: g.FieldsFromStruct(&Cow{}),
+	}
+
+	if r.SchemaVersion() != 0 {
+		t.Fatalf("Expected schema version 0, given: %d", r.SchemaVersion())
+	}
+	err := r.GenerateStateUpgraderCode(bytes.NewBuffer([]byte{}))
+	if err == nil {
+		t.Fatal("Expected error for compatible schema")
+	}
+}
+
+var state_upgraders_resource_snippet = `		Schema: cowSchema,
+
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCattleCowV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceCattleCowStateUpgradeV0,
+			},
+			{
+				Version: 1,
+				Type:    resourceCattleCowV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceCattleCowStateUpgradeV1,
+			},
+		},
+	}
+}
+`
+
+var state_upgrader_sdkv2_output = `package cattle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Schema of version 1, decoding the state written by previous releases
+func resourceCattleCowV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"age": {
+Type: schema.TypeInt,
+Optional: true,
+},
+			"barn": {
+Type: schema.TypeInt,
+Optional: true,
+},
+			"name": {
+Type: schema.TypeString,
+Required: true,
+},
+			"owner": {
+Type: schema.TypeList,
+Optional: true,
+MaxItems: 1,
+Elem: &schema.Resource{
+Schema: map[string]*schema.Schema{
+"farm": {
+Type: schema.TypeString,
+Optional: true,
+},
+"name": {
+Type: schema.TypeString,
+Required: true,
+},
+},
+},
+},
+			"weight": {
+Type: schema.TypeFloat,
+Required: true,
+},
+		},
+	}
+}
+
+func resourceCattleCowStateUpgradeV1(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	// age: type changed from TypeInt to TypeString
+	if v, ok := rawState["age"]; ok && v != nil {
+		rawState["age"] = fmt.Sprint(v)
+	}
+	// barn: field removed
+	delete(rawState, "barn")
+	// name: renamed to full_name
+	if v, ok := rawState["name"]; ok {
+		rawState["full_name"] = v
+		delete(rawState, "name")
+	}
+	// TODO: owner.farm: field removed
+
+	return rawState, nil
+}
+`
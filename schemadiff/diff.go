@@ -0,0 +1,246 @@
+package schemadiff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+type ChangeKind string
+
+const (
+	FieldRemoved    ChangeKind = "field_removed"
+	FieldAdded      ChangeKind = "field_added"
+	TypeChanged     ChangeKind = "type_changed"
+	ModeChanged     ChangeKind = "mode_changed"
+	ForceNewAdded   ChangeKind = "force_new_added"
+	ForceNewRemoved ChangeKind = "force_new_removed"
+	MaxItemsChanged ChangeKind = "max_items_changed"
+	DefaultChanged  ChangeKind = "default_changed"
+	ResourceRemoved ChangeKind = "resource_removed"
+	ResourceAdded   ChangeKind = "resource_added"
+)
+
+// Change of a resource, data source or the provider configuration
+type Change struct {
+	// resource, data_source or provider
+	BlockType string `json:"block_type"`
+	// e.g. kubernetes_pod (empty for provider)
+	BlockName string `json:"block_name,omitempty"`
+	// e.g. metadata.name (empty if the whole block was added/removed)
+	Path string `json:"path,omitempty"`
+
+	Kind     ChangeKind `json:"kind"`
+	Breaking bool       `json:"breaking"`
+	Message  string     `json:"message"`
+}
+
+// Providers compares schemas of the provider configuration,
+// resources and data sources
+func Providers(old, new *schema.Provider) []*Change {
+	changes := Resources(&schema.Resource{Schema: old.Schema}, &schema.Resource{Schema: new.Schema})
+	for _, c := range changes {
+		c.BlockType = "provider"
+	}
+	changes = append(changes, diffResourceMaps("resource", old.ResourcesMap, new.ResourcesMap)...)
+	changes = append(changes, diffResourceMaps("data_source", old.DataSourcesMap, new.DataSourcesMap)...)
+	return changes
+}
+
+// Resources compares fields of two resources, including nested blocks
+func Resources(old, new *schema.Resource) []*Change {
+	oldFields := fieldsByPath(old)
+	newFields := fieldsByPath(new)
+
+	changes := make([]*Change, 0)
+	for path, o := range oldFields {
+		n, ok := newFields[path]
+		if !ok {
+			// Only the outermost removed block is reported
+			if p := parentPath(path); hasField(oldFields, p) && !hasField(newFields, p) {
+				continue
+			}
+			changes = append(changes, &Change{
+				Path:     path,
+				Kind:     FieldRemoved,
+				Breaking: true,
+				Message:  "field removed",
+			})
+			continue
+		}
+		changes = append(changes, diffFields(path, o, n)...)
+	}
+	for path, n := range newFields {
+		if _, ok := oldFields[path]; ok {
+			continue
+		}
+		if p := parentPath(path); hasField(newFields, p) && !hasField(oldFields, p) {
+			continue
+		}
+		changes = append(changes, &Change{
+			Path: path,
+			Kind: FieldAdded,
+			// Existing configurations don't set it
+			Breaking: n.Required,
+			Message:  fmt.Sprintf("field added (%s)", mode(n)),
+		})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Path != changes[j].Path {
+			return changes[i].Path < changes[j].Path
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+	return changes
+}
+
+// HasBreaking is true if any of the changes is breaking
+func HasBreaking(changes []*Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+func diffResourceMaps(blockType string, old, new map[string]*schema.Resource) []*Change {
+	changes := make([]*Change, 0)
+	for _, name := range sortedNames(old, new) {
+		o, inOld := old[name]
+		n, inNew := new[name]
+
+		var blockChanges []*Change
+		switch {
+		case !inNew:
+			blockChanges = []*Change{{
+				Kind:     ResourceRemoved,
+				Breaking: true,
+				Message:  strings.Replace(blockType, "_", " ", -1) + " removed",
+			}}
+		case !inOld:
+			blockChanges = []*Change{{
+				Kind:    ResourceAdded,
+				Message: strings.Replace(blockType, "_", " ", -1) + " added",
+			}}
+		default:
+			blockChanges = Resources(o, n)
+		}
+
+		for _, c := range blockChanges {
+			c.BlockType = blockType
+			c.BlockName = name
+		}
+		changes = append(changes, blockChanges...)
+	}
+	return changes
+}
+
+func diffFields(path string, o, n *schema.Schema) []*Change {
+	changes := make([]*Change, 0)
+	add := func(kind ChangeKind, breaking bool, format string, a ...interface{}) {
+		changes = append(changes, &Change{
+			Path:     path,
+			Kind:     kind,
+			Breaking: breaking,
+			Message:  fmt.Sprintf(format, a...),
+		})
+	}
+
+	if oldType, newType := typeName(o), typeName(n); oldType != newType {
+		add(TypeChanged, true, "type changed from %s to %s", oldType, newType)
+	}
+
+	if oldMode, newMode := mode(o), mode(n); oldMode != newMode {
+		// Any change making existing configurations invalid is breaking
+		breaking := newMode == "Required" || newMode == "Computed"
+		add(ModeChanged, breaking, "changed from %s to %s", oldMode, newMode)
+	}
+
+	switch {
+	case !o.ForceNew && n.ForceNew:
+		add(ForceNewAdded, true, "ForceNew added")
+	case o.ForceNew && !n.ForceNew:
+		add(ForceNewRemoved, false, "ForceNew removed")
+	}
+
+	if o.MaxItems != n.MaxItems {
+		// 0 stands for unlimited
+		breaking := n.MaxItems > 0 && (o.MaxItems == 0 || n.MaxItems < o.MaxItems)
+		add(MaxItemsChanged, breaking, "MaxItems changed from %d to %d", o.MaxItems, n.MaxItems)
+	}
+
+	// Resources which don't set the field would be updated
+	if !reflect.DeepEqual(o.Default, n.Default) {
+		add(DefaultChanged, true, "Default changed from %#v to %#v", o.Default, n.Default)
+	}
+
+	return changes
+}
+
+// e.g. TypeList of TypeString, or TypeSet of block
+func typeName(s *schema.Schema) string {
+	switch elem := s.Elem.(type) {
+	case *schema.Schema:
+		return fmt.Sprintf("%s of %s", s.Type, elem.Type)
+	case *schema.Resource:
+		return fmt.Sprintf("%s of block", s.Type)
+	}
+	return s.Type.String()
+}
+
+// Optional+Computed is treated as Optional
+func mode(s *schema.Schema) string {
+	switch {
+	case s.Required:
+		return "Required"
+	case s.Optional:
+		return "Optional"
+	case s.Computed:
+		return "Computed"
+	}
+	return "unknown"
+}
+
+func fieldsByPath(res *schema.Resource) map[string]*schema.Schema {
+	m := make(map[string]*schema.Schema, 0)
+	if res == nil {
+		return m
+	}
+	u.WalkResource(res, func(path []string, s *schema.Schema) {
+		m[strings.Join(path, ".")] = s
+	})
+	return m
+}
+
+func hasField(m map[string]*schema.Schema, path string) bool {
+	_, ok := m[path]
+	return ok
+}
+
+func parentPath(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+func sortedNames(maps ...map[string]*schema.Resource) []string {
+	seen := make(map[string]bool, 0)
+	names := make([]string, 0)
+	for _, m := range maps {
+		for name := range m {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
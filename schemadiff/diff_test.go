@@ -0,0 +1,138 @@
+package schemadiff
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestProviders(t *testing.T) {
+	old := &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"token": {Type: schema.TypeString, Optional: true},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"cattle_cow": {
+				Schema: map[string]*schema.Schema{
+					"name":   {Type: schema.TypeString, Optional: true},
+					"age":    {Type: schema.TypeInt, Optional: true},
+					"weight": {Type: schema.TypeString, Required: true},
+					"tags": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+					"owner": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {Type: schema.TypeString, Required: true},
+								"farm": {Type: schema.TypeString, Optional: true},
+							},
+						},
+					},
+					"barn": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"number": {Type: schema.TypeInt, Optional: true},
+							},
+						},
+					},
+				},
+			},
+			"cattle_bull": {Schema: map[string]*schema.Schema{}},
+		},
+	}
+	new := &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"token": {Type: schema.TypeString, Optional: true},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"cattle_cow": {
+				Schema: map[string]*schema.Schema{
+					"name":   {Type: schema.TypeString, Required: true, ForceNew: true},
+					"age":    {Type: schema.TypeString, Optional: true},
+					"weight": {Type: schema.TypeString, Optional: true},
+					"tags": {
+						Type:     schema.TypeSet,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+					"owner": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {Type: schema.TypeString, Required: true},
+							},
+						},
+					},
+					"color": {Type: schema.TypeString, Optional: true, Default: "brown"},
+				},
+			},
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"cattle_cow": {Schema: map[string]*schema.Schema{}},
+		},
+	}
+
+	changes := Providers(old, new)
+	expectedChanges := []*Change{
+		{BlockType: "resource", BlockName: "cattle_bull", Kind: ResourceRemoved, Breaking: true, Message: "resource removed"},
+		{BlockType: "resource", BlockName: "cattle_cow", Path: "age", Kind: TypeChanged, Breaking: true, Message: "type changed from TypeInt to TypeString"},
+		{BlockType: "resource", BlockName: "cattle_cow", Path: "barn", Kind: FieldRemoved, Breaking: true, Message: "field removed"},
+		{BlockType: "resource", BlockName: "cattle_cow", Path: "color", Kind: FieldAdded, Breaking: false, Message: "field added (Optional)"},
+		{BlockType: "resource", BlockName: "cattle_cow", Path: "name", Kind: ForceNewAdded, Breaking: true, Message: "ForceNew added"},
+		{BlockType: "resource", BlockName: "cattle_cow", Path: "name", Kind: ModeChanged, Breaking: true, Message: "changed from Optional to Required"},
+		{BlockType: "resource", BlockName: "cattle_cow", Path: "owner", Kind: MaxItemsChanged, Breaking: true, Message: "MaxItems changed from 0 to 1"},
+		{BlockType: "resource", BlockName: "cattle_cow", Path: "owner.farm", Kind: FieldRemoved, Breaking: true, Message: "field removed"},
+		{BlockType: "resource", BlockName: "cattle_cow", Path: "tags", Kind: TypeChanged, Breaking: true, Message: "type changed from TypeList of TypeString to TypeSet of TypeString"},
+		{BlockType: "resource", BlockName: "cattle_cow", Path: "weight", Kind: ModeChanged, Breaking: false, Message: "changed from Required to Optional"},
+		{BlockType: "data_source", BlockName: "cattle_cow", Kind: ResourceAdded, Breaking: false, Message: "data source added"},
+	}
+	if !reflect.DeepEqual(changes, expectedChanges) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedChanges, changes)
+	}
+	if !HasBreaking(changes) {
+		t.Fatal("Expected breaking changes")
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := WriteText(buf, changes[9:]); err != nil {
+		t.Fatal(err)
+	}
+	expectedText := `          resource cattle_cow: weight: changed from Required to Optional
+          data source cattle_cow: data source added
+0 breaking, 2 non-breaking change(s)
+`
+	if buf.String() != expectedText {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedText, buf.String())
+	}
+}
+
+func TestResources_unchanged(t *testing.T) {
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true, Computed: true},
+		},
+	}
+	changes := Resources(res, res)
+	if len(changes) != 0 {
+		t.Fatalf("Expected no changes, given: %s", changes)
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := WriteJSON(buf, changes); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Fatalf("Unexpected JSON: %q", buf.String())
+	}
+}
@@ -0,0 +1,50 @@
+package schemadiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteText writes one change per line followed by a summary, e.g.
+// BREAKING  resource kubernetes_pod: metadata.name: changed from Optional to Required
+func WriteText(w io.Writer, changes []*Change) error {
+	breaking := 0
+	for _, c := range changes {
+		prefix := "          "
+		if c.Breaking {
+			prefix = "BREAKING  "
+			breaking++
+		}
+		if _, err := fmt.Fprintf(w, "%s%s\n", prefix, c); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d breaking, %d non-breaking change(s)\n",
+		breaking, len(changes)-breaking)
+	return err
+}
+
+// WriteJSON writes changes as a JSON array
+func WriteJSON(w io.Writer, changes []*Change) error {
+	if changes == nil {
+		changes = []*Change{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(changes)
+}
+
+func (c *Change) String() string {
+	parts := make([]string, 0, 3)
+	if c.BlockType != "" {
+		parts = append(parts, strings.TrimSpace(strings.Replace(c.BlockType, "_", " ", -1)+" "+c.BlockName))
+	}
+	if c.Path != "" {
+		parts = append(parts, c.Path)
+	}
+	parts = append(parts, c.Message)
+	return strings.Join(parts, ": ")
+}
@@ -0,0 +1,81 @@
+package schemagen
+
+import (
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// InferAttributesFilter is the default FilterFunc, it accepts every field
+// (except those tagged tf:"-") and infers its attributes via InferAttributes
+func InferAttributesFilter(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+	if sf.Tag.Get("tf") == "-" {
+		return k, false
+	}
+	InferAttributes(sf, s)
+	return k, true
+}
+
+// InferAttributes sets Required/Optional/Computed/ForceNew/Default
+// from an explicit tag (e.g. tf:"optional,computed,forcenew" or tf:"default=80"),
+// otherwise pointers, omitempty & defaulted fields are Optional, the rest Required.
+// Default without a value is the zero value of the field's type, default=
+// is the last option, so that its value may contain commas (e.g. tf:"default=a,b").
+func InferAttributes(sf *reflect.StructField, s *schema.Schema) {
+	explicit := false
+	opts := strings.Split(sf.Tag.Get("tf"), ",")
+	for i, opt := range opts {
+		opt = strings.TrimSpace(opt)
+		// Converted to the field's type once it's known, see normalizeDefault
+		if opt == "default" {
+			s.Default = ""
+			continue
+		}
+		if strings.HasPrefix(opt, "default=") {
+			s.Default = strings.TrimPrefix(strings.TrimSpace(strings.Join(opts[i:], ",")), "default=")
+			break
+		}
+		switch opt {
+		case "required":
+			s.Required = true
+			explicit = true
+		case "optional":
+			s.Optional = true
+			explicit = true
+		case "computed":
+			s.Computed = true
+			explicit = true
+		case "forcenew":
+			s.ForceNew = true
+		}
+	}
+	// The SDK only accepts defaults of fields which are optional & not computed
+	if s.Default != nil && (s.Required || s.Computed) {
+		log.Printf("ERROR: %s: Default conflicts with required/computed - SKIPPING default", sf.Name)
+		s.Default = nil
+	}
+	if explicit {
+		return
+	}
+
+	if sf.Type.Kind() == reflect.Ptr || hasOmitEmpty(sf) || s.Default != nil {
+		s.Optional = true
+		return
+	}
+	s.Required = true
+}
+
+func hasOmitEmpty(sf *reflect.StructField) bool {
+	for _, opt := range strings.Split(sf.Tag.Get("json"), ",")[1:] {
+		if opt == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
+
+func noDocs(iface interface{}, sf *reflect.StructField) string {
+	return ""
+}
@@ -0,0 +1,46 @@
+package schemagen
+
+// Backend is the SDK (or framework) the generated code is written for,
+// generators themselves always work with the in-memory SDKv1 schema
+type Backend string
+
+const (
+	// github.com/hashicorp/terraform/helper/schema (default)
+	SDKv1 Backend = ""
+	// github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema
+	SDKv2 Backend = "sdkv2"
+	// github.com/hashicorp/terraform-plugin-framework
+	Framework Backend = "framework"
+)
+
+func (b Backend) SchemaImportPath() string {
+	switch b {
+	case SDKv2:
+		return "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	case Framework:
+		return "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	}
+	return "github.com/hashicorp/terraform/helper/schema"
+}
+
+// Framework schema refers to validators instead, see RenderFrameworkSchema
+func (b Backend) ValidationImportPath() string {
+	switch b {
+	case SDKv2:
+		return "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	case Framework:
+		return ""
+	}
+	return "github.com/hashicorp/terraform/helper/validation"
+}
+
+// Package providing resource.Test for acceptance tests
+func (b Backend) TestingImportPath() string {
+	switch b {
+	case SDKv2:
+		return "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	case Framework:
+		return "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	}
+	return "github.com/hashicorp/terraform/helper/resource"
+}
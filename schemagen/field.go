@@ -0,0 +1,86 @@
+package schemagen
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Field is a node of the schema tree built by FieldsFromStruct
+type Field struct {
+	// Key in the parent's map[string]*schema.Schema (empty for Elem)
+	Name string
+
+	// Schema.Elem is always nil, see Elem & Block instead
+	Schema *schema.Schema
+	Funcs  *SchemaFuncs
+
+	// Element of a TypeSet/TypeList of primitives
+	Elem *Field
+	// Nested block of TypeSet/TypeList
+	Block *Block
+}
+
+// Block is a nested *schema.Resource
+type Block struct {
+	// Set if the block is lifted into a shared function (see SharedSchemaFuncs)
+	FuncName string
+	Fields   []*Field
+}
+
+// Names of functions referenced from the generated schema code
+type SchemaFuncs struct {
+	Set              string
+	StateFunc        string
+	DiffSuppressFunc string
+	ValidateFunc     string
+}
+
+// SDKSchema returns a copy of the schema with Elem populated recursively,
+// functions are left out as these only exist as names in the generated code
+func (f *Field) SDKSchema() *schema.Schema {
+	s := *f.Schema
+	if f.Elem != nil {
+		s.Elem = f.Elem.SDKSchema()
+	}
+	if f.Block != nil {
+		s.Elem = f.Block.SDKResource()
+	}
+	return &s
+}
+
+func (b *Block) SDKResource() *schema.Resource {
+	m := make(map[string]*schema.Schema, len(b.Fields))
+	for _, f := range b.Fields {
+		m[f.Name] = f.SDKSchema()
+	}
+	return &schema.Resource{Schema: m}
+}
+
+// FieldsFromResource is the inverse of Block.SDKResource, e.g. to render
+// a previously recorded schema, fields are sorted by name
+func FieldsFromResource(res *schema.Resource) []*Field {
+	fields := make([]*Field, 0, len(res.Schema))
+	for name, s := range res.Schema {
+		f := fieldFromSchema(s)
+		f.Name = name
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+	return fields
+}
+
+func fieldFromSchema(s *schema.Schema) *Field {
+	copied := *s
+	copied.Elem = nil
+	f := &Field{Schema: &copied, Funcs: &SchemaFuncs{}}
+	switch elem := s.Elem.(type) {
+	case *schema.Schema:
+		f.Elem = fieldFromSchema(elem)
+	case *schema.Resource:
+		f.Block = &Block{Fields: FieldsFromResource(elem)}
+	}
+	return f
+}
@@ -0,0 +1,108 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestFieldsFromStruct_transform(t *testing.T) {
+	type NestedStruct struct {
+		MyInt int
+	}
+	type SimpleStruct struct {
+		Nested *NestedStruct
+		MyTags []string
+		MyBool bool
+	}
+
+	g := &SchemaGenerator{}
+	fields := g.FieldsFromStruct(&SimpleStruct{})
+
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	expectedNames := []string{"my_bool", "my_tags", "nested"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedNames, names)
+	}
+
+	// Fields can be adjusted before rendering
+	fields[0].Schema.Description = "Whether it's enabled"
+	fields[2].Block.Fields[0].Schema.ForceNew = true
+
+	rendered, err := RenderFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRendered := map[string]string{
+		"my_bool": "{\nType: schema.TypeBool,\nDescription: \"Whether it's enabled\",\nRequired: true,\n}",
+		"my_tags": "{\nType: schema.TypeSet,\nRequired: true,\nElem: &schema.Schema{Type: schema.TypeString,},\nSet: schema.HashString,\n}",
+		"nested":  "{\nType: schema.TypeList,\nOptional: true,\nMaxItems: 1,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"my_int\": {\nType: schema.TypeInt,\nRequired: true,\nForceNew: true,\n},\n},\n},\n}",
+	}
+	if !reflect.DeepEqual(rendered, expectedRendered) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedRendered, rendered)
+	}
+
+	sdkSchema := fields[2].SDKSchema()
+	expectedSDKSchema := &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"my_int": {
+					Type:     schema.TypeInt,
+					Required: true,
+					ForceNew: true,
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(sdkSchema, expectedSDKSchema) {
+		t.Fatalf("Expected: %#v\n\nGiven: %#v\n", expectedSDKSchema, sdkSchema)
+	}
+}
+
+func TestFieldsFromResource(t *testing.T) {
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"nested": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"my_int": {Type: schema.TypeInt, Computed: true},
+					},
+				},
+			},
+		},
+	}
+
+	fields := FieldsFromResource(res)
+	rendered, err := RenderFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRendered := map[string]string{
+		"name":   "{\nType: schema.TypeString,\nRequired: true,\n}",
+		"tags":   "{\nType: schema.TypeSet,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\n}",
+		"nested": "{\nType: schema.TypeList,\nOptional: true,\nMaxItems: 1,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"my_int\": {\nType: schema.TypeInt,\nComputed: true,\n},\n},\n},\n}",
+	}
+	if !reflect.DeepEqual(rendered, expectedRendered) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedRendered, rendered)
+	}
+
+	if given := (&Block{Fields: fields}).SDKResource(); !reflect.DeepEqual(given, res) {
+		t.Fatalf("Expected: %#v\n\nGiven: %#v\n", res, given)
+	}
+}
@@ -0,0 +1,376 @@
+package schemagen
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// FrameworkSchema holds the code of a terraform-plugin-framework schema.Schema
+type FrameworkSchema struct {
+	// Entries of schema.Schema's Attributes & Blocks
+	Attributes map[string]string
+	Blocks     map[string]string
+	// Packages the code refers to, besides schema
+	Imports []string
+}
+
+// Code of the whole schema.Schema literal
+func (fs *FrameworkSchema) Code() string {
+	return "schema.Schema{\n" + frameworkAttributesCode(fs.Attributes) +
+		frameworkBlocksCode(fs.Blocks) + "}"
+}
+
+// RenderFrameworkSchema renders fields for the framework backend,
+// nested blocks which are Computed-only become nested attributes.
+// Only validation.StringInSlice & IntInSlice have equivalent validators,
+// other functions (e.g. StateFunc) are skipped.
+// ForceNew fields get RequiresReplace (or UseStateForUnknown if Computed) plan modifier
+// and primitive fields with Default become Computed, as the framework requires.
+func RenderFrameworkSchema(fields []*Field) (*FrameworkSchema, error) {
+	return renderFrameworkSchema(fields, false)
+}
+
+// RenderFrameworkDataSourceSchema renders fields for datasource/schema,
+// which has neither plan modifiers, nor defaults
+func RenderFrameworkDataSourceSchema(fields []*Field) (*FrameworkSchema, error) {
+	return renderFrameworkSchema(fields, true)
+}
+
+func renderFrameworkSchema(fields []*Field, dataSource bool) (*FrameworkSchema, error) {
+	r := &frameworkRenderer{imports: make(map[string]bool, 0), dataSource: dataSource}
+	attrs, blocks, err := r.renderFields(fields, false, false)
+	if err != nil {
+		return nil, err
+	}
+	return &FrameworkSchema{
+		Attributes: attrs,
+		Blocks:     blocks,
+		Imports:    sortedImports(r.imports),
+	}, nil
+}
+
+// WithIDField adds a computed id attribute (unless it's present),
+// which framework resources keep their ID in.
+// ID never changes, so it's ForceNew, i.e. kept in the plan via UseStateForUnknown.
+func WithIDField(fields []*Field) []*Field {
+	for _, f := range fields {
+		if f.Name == "id" {
+			return fields
+		}
+	}
+	id := &Field{
+		Name:   "id",
+		Schema: &schema.Schema{Type: schema.TypeString, Computed: true, ForceNew: true},
+		Funcs:  &SchemaFuncs{},
+	}
+	fields = append(append([]*Field{}, fields...), id)
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+	return fields
+}
+
+const (
+	frameworkTypesImportPath     = "github.com/hashicorp/terraform-plugin-framework/types"
+	frameworkAttrImportPath      = "github.com/hashicorp/terraform-plugin-framework/attr"
+	frameworkValidatorImportPath = "github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	frameworkValidatorsPkgPath   = "github.com/hashicorp/terraform-plugin-framework-validators/"
+	frameworkResourceSchemaPath  = "github.com/hashicorp/terraform-plugin-framework/resource/schema/"
+)
+
+type frameworkRenderer struct {
+	imports    map[string]bool
+	dataSource bool
+}
+
+// Nested attributes can't contain blocks, so asAttributes applies to all descendants,
+// same as computedOnly, as attributes of a Computed-only parent can't be set either
+func (r *frameworkRenderer) renderFields(fields []*Field, asAttributes, computedOnly bool) (map[string]string, map[string]string, error) {
+	attrs := make(map[string]string, 0)
+	blocks := make(map[string]string, 0)
+	for _, f := range fields {
+		if f.Block != nil && !asAttributes && !isComputedOnly(f.Schema) {
+			code, err := r.renderBlock(f)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s", f.Name, err)
+			}
+			blocks[f.Name] = code
+			continue
+		}
+
+		code, err := r.renderAttribute(f, computedOnly)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", f.Name, err)
+		}
+		attrs[f.Name] = code
+	}
+	return attrs, blocks, nil
+}
+
+func (r *frameworkRenderer) renderBlock(f *Field) (string, error) {
+	attrs, blocks, err := r.renderFields(f.Block.Fields, false, false)
+	if err != nil {
+		return "", err
+	}
+	collection := frameworkCollection(f.Schema.Type)
+
+	code := fmt.Sprintf("schema.%sNestedBlock{\n", collection)
+	if f.Schema.Description != "" {
+		code += fmt.Sprintf("Description: %q,\n", f.Schema.Description)
+	}
+	code += "NestedObject: schema.NestedBlockObject{\n" +
+		frameworkAttributesCode(attrs) + frameworkBlocksCode(blocks) + "},\n"
+
+	// Blocks can be neither Required nor Optional
+	validators := make([]string, 0)
+	if f.Schema.Required {
+		validators = append(validators, r.validatorFunc(collection, "IsRequired()"))
+	}
+	if f.Schema.MaxItems > 0 {
+		validators = append(validators, r.validatorFunc(collection, fmt.Sprintf("SizeAtMost(%d)", f.Schema.MaxItems)))
+	}
+	code += r.validatorsCode(collection, validators)
+	// Blocks are never Computed, so there's no state to keep
+	code += r.planModifiersCode(collection, f.Schema.ForceNew, false)
+
+	return code + "}", nil
+}
+
+func (r *frameworkRenderer) renderAttribute(f *Field, computedOnly bool) (string, error) {
+	s := f.Schema
+	if computedOnly {
+		s = &schema.Schema{
+			Type:        s.Type,
+			Description: s.Description,
+			Computed:    true,
+			Sensitive:   s.Sensitive,
+		}
+	}
+	computedOnly = isComputedOnly(s)
+
+	var code string
+	validators := make([]string, 0)
+
+	switch {
+	case f.Block != nil:
+		attrs, _, err := r.renderFields(f.Block.Fields, true, computedOnly)
+		if err != nil {
+			return "", err
+		}
+		code = fmt.Sprintf("schema.%sNestedAttribute{\n", frameworkCollection(s.Type)) +
+			"NestedObject: schema.NestedAttributeObject{\n" + frameworkAttributesCode(attrs) + "},\n"
+	case s.Type == schema.TypeList || s.Type == schema.TypeSet || s.Type == schema.TypeMap:
+		elemType := schema.TypeString
+		if f.Elem != nil {
+			elemType = f.Elem.Schema.Type
+			if v, ok := r.validator(f.Name, f.Elem.Funcs); ok {
+				validators = append(validators, r.validatorFunc(frameworkCollection(s.Type),
+					fmt.Sprintf("Value%ssAre(%s)", frameworkPrimitive(elemType), v)))
+			}
+		}
+		r.imports[frameworkTypesImportPath] = true
+		code = fmt.Sprintf("schema.%sAttribute{\nElementType: types.%sType,\n",
+			frameworkCollection(s.Type), frameworkPrimitive(elemType))
+	default:
+		if v, ok := r.validator(f.Name, f.Funcs); ok {
+			validators = append(validators, v)
+		}
+		code = fmt.Sprintf("schema.%sAttribute{\n", frameworkPrimitive(s.Type))
+	}
+
+	def := r.defaultCode(f.Name, s)
+	if s.Description != "" {
+		code += fmt.Sprintf("Description: %q,\n", s.Description)
+	}
+	if s.Required {
+		code += "Required: true,\n"
+	}
+	if s.Optional {
+		code += "Optional: true,\n"
+	}
+	if s.Computed || def != "" {
+		code += "Computed: true,\n"
+	}
+	if s.Sensitive {
+		code += "Sensitive: true,\n"
+	}
+	if def != "" {
+		code += fmt.Sprintf("Default: %s,\n", def)
+	}
+	if s.MaxItems > 0 && s.Type != schema.TypeMap {
+		validators = append(validators, r.validatorFunc(frameworkCollection(s.Type), fmt.Sprintf("SizeAtMost(%d)", s.MaxItems)))
+	}
+
+	// Validators only apply to configuration
+	if computedOnly {
+		validators = nil
+	}
+	typeName := frameworkPrimitive(s.Type)
+	if f.Block != nil || s.Type == schema.TypeList || s.Type == schema.TypeSet || s.Type == schema.TypeMap {
+		typeName = frameworkCollection(s.Type)
+	}
+	code += r.validatorsCode(typeName, validators)
+	code += r.planModifiersCode(typeName, s.ForceNew && (s.Required || s.Optional), s.ForceNew && s.Computed)
+
+	return code + "}", nil
+}
+
+// e.g. int64default.StaticInt64(80), collections aren't supported
+func (r *frameworkRenderer) defaultCode(name string, s *schema.Schema) string {
+	if s.Default == nil || r.dataSource {
+		return ""
+	}
+	if _, ok := primitiveTypes[s.Type]; !ok {
+		log.Printf("WARN: %s: Default of %s is not supported by the framework backend - SKIPPING", name, s.Type)
+		return ""
+	}
+	typeName := frameworkPrimitive(s.Type)
+	pkgName := strings.ToLower(typeName) + "default"
+	r.imports[frameworkResourceSchemaPath+pkgName] = true
+	return fmt.Sprintf("%s.Static%s(%s)", pkgName, typeName, defaultCode(s.Default))
+}
+
+var primitiveTypes = map[schema.ValueType]bool{
+	schema.TypeString: true, schema.TypeInt: true, schema.TypeFloat: true, schema.TypeBool: true,
+}
+
+// Changes of immutable attributes require replacement
+// and Computed ones keep their value from the state
+func (r *frameworkRenderer) planModifiersCode(typeName string, requiresReplace, useStateForUnknown bool) string {
+	if r.dataSource || (!requiresReplace && !useStateForUnknown) {
+		return ""
+	}
+	pkgName := strings.ToLower(typeName) + "planmodifier"
+	r.imports[frameworkResourceSchemaPath+"planmodifier"] = true
+	r.imports[frameworkResourceSchemaPath+pkgName] = true
+
+	code := fmt.Sprintf("PlanModifiers: []planmodifier.%s{\n", typeName)
+	if requiresReplace {
+		code += pkgName + ".RequiresReplace(),\n"
+	}
+	if useStateForUnknown {
+		code += pkgName + ".UseStateForUnknown(),\n"
+	}
+	return code + "},\n"
+}
+
+var inSliceRegexp = regexp.MustCompile(`^validation\.(String|Int)InSlice\(\[\](?:string|int)\{(.*)\}(?:, (true|false))?\)$`)
+
+// Equivalent of the ValidateFunc (if any), other functions don't have one
+func (r *frameworkRenderer) validator(name string, funcs *SchemaFuncs) (string, bool) {
+	if funcs == nil {
+		return "", false
+	}
+	for _, fn := range []string{funcs.StateFunc, funcs.DiffSuppressFunc} {
+		if fn != "" {
+			log.Printf("WARN: %s: %s is not supported by the framework backend - SKIPPING", name, fn)
+		}
+	}
+	if funcs.ValidateFunc == "" {
+		return "", false
+	}
+
+	m := inSliceRegexp.FindStringSubmatch(funcs.ValidateFunc)
+	if m == nil {
+		log.Printf("WARN: %s: %s is not supported by the framework backend - SKIPPING", name, funcs.ValidateFunc)
+		return "", false
+	}
+	if m[1] == "Int" {
+		return r.validatorFunc("Int64", "OneOf("+m[2]+")"), true
+	}
+	if m[3] == "true" {
+		return r.validatorFunc("String", "OneOfCaseInsensitive("+m[2]+")"), true
+	}
+	return r.validatorFunc("String", "OneOf("+m[2]+")"), true
+}
+
+// e.g. listvalidator.SizeAtMost(1)
+func (r *frameworkRenderer) validatorFunc(typeName, call string) string {
+	pkgName := strings.ToLower(typeName) + "validator"
+	r.imports[frameworkValidatorsPkgPath+pkgName] = true
+	return pkgName + "." + call
+}
+
+func (r *frameworkRenderer) validatorsCode(typeName string, validators []string) string {
+	if len(validators) == 0 {
+		return ""
+	}
+	r.imports[frameworkValidatorImportPath] = true
+	code := fmt.Sprintf("Validators: []validator.%s{\n", typeName)
+	for _, v := range validators {
+		code += v + ",\n"
+	}
+	return code + "},\n"
+}
+
+func frameworkAttributesCode(attrs map[string]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	code := "Attributes: map[string]schema.Attribute{\n"
+	for _, name := range sortedNames(attrs) {
+		code += fmt.Sprintf("%q: %s,\n", name, attrs[name])
+	}
+	return code + "},\n"
+}
+
+func frameworkBlocksCode(blocks map[string]string) string {
+	if len(blocks) == 0 {
+		return ""
+	}
+	code := "Blocks: map[string]schema.Block{\n"
+	for _, name := range sortedNames(blocks) {
+		code += fmt.Sprintf("%q: %s,\n", name, blocks[name])
+	}
+	return code + "},\n"
+}
+
+// Names of attribute/value types, e.g. String in schema.StringAttribute & types.String
+func frameworkPrimitive(t schema.ValueType) string {
+	switch t {
+	case schema.TypeInt:
+		return "Int64"
+	case schema.TypeFloat:
+		return "Float64"
+	case schema.TypeBool:
+		return "Bool"
+	}
+	return "String"
+}
+
+func frameworkCollection(t schema.ValueType) string {
+	switch t {
+	case schema.TypeSet:
+		return "Set"
+	case schema.TypeMap:
+		return "Map"
+	}
+	return "List"
+}
+
+func isComputedOnly(s *schema.Schema) bool {
+	return s.Computed && !s.Optional && !s.Required
+}
+
+func sortedNames(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedImports(imports map[string]bool) []string {
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,119 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestRenderFrameworkSchema(t *testing.T) {
+	type NestedStruct struct {
+		Phase string
+	}
+	type SimpleStruct struct {
+		Name   string
+		Tags   []string `tf:"optional"`
+		Nested *NestedStruct
+		Status *NestedStruct `tf:"computed"`
+	}
+
+	g := &SchemaGenerator{}
+	fields := WithIDField(g.FieldsFromStruct(&SimpleStruct{}))
+	fields[1].Funcs.ValidateFunc = `validation.StringInSlice([]string{"cow", "bull"}, false)`
+
+	fs, err := RenderFrameworkSchema(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSchema := &FrameworkSchema{
+		Attributes: map[string]string{
+			"id":     "schema.StringAttribute{\nComputed: true,\nPlanModifiers: []planmodifier.String{\nstringplanmodifier.UseStateForUnknown(),\n},\n}",
+			"name":   "schema.StringAttribute{\nRequired: true,\nValidators: []validator.String{\nstringvalidator.OneOf(\"cow\", \"bull\"),\n},\n}",
+			"status": "schema.ListNestedAttribute{\nNestedObject: schema.NestedAttributeObject{\nAttributes: map[string]schema.Attribute{\n\"phase\": schema.StringAttribute{\nComputed: true,\n},\n},\n},\nComputed: true,\n}",
+			"tags":   "schema.SetAttribute{\nElementType: types.StringType,\nOptional: true,\n}",
+		},
+		Blocks: map[string]string{
+			"nested": "schema.ListNestedBlock{\nNestedObject: schema.NestedBlockObject{\nAttributes: map[string]schema.Attribute{\n\"phase\": schema.StringAttribute{\nRequired: true,\n},\n},\n},\nValidators: []validator.List{\nlistvalidator.SizeAtMost(1),\n},\n}",
+		},
+		Imports: []string{
+			"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator",
+			"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator",
+			"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier",
+			"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier",
+			"github.com/hashicorp/terraform-plugin-framework/schema/validator",
+			"github.com/hashicorp/terraform-plugin-framework/types",
+		},
+	}
+	if !reflect.DeepEqual(fs, expectedSchema) {
+		t.Fatalf("Expected: %#v\n\nGiven: %#v\n", expectedSchema, fs)
+	}
+}
+
+func TestRenderFrameworkSchema_defaultsAndPlanModifiers(t *testing.T) {
+	type NestedStruct struct {
+		Phase string
+	}
+	type SimpleStruct struct {
+		Name     string        `tf:"forcenew"`
+		Port     int           `tf:"default=80"`
+		Replicas int           `tf:"optional,computed,forcenew"`
+		Tags     []string      `tf:"optional,default"`
+		Nested   *NestedStruct `tf:"optional,forcenew"`
+	}
+
+	g := &SchemaGenerator{}
+	fields := g.FieldsFromStruct(&SimpleStruct{})
+
+	fs, err := RenderFrameworkSchema(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSchema := &FrameworkSchema{
+		Attributes: map[string]string{
+			"name":     "schema.StringAttribute{\nRequired: true,\nPlanModifiers: []planmodifier.String{\nstringplanmodifier.RequiresReplace(),\n},\n}",
+			"port":     "schema.Int64Attribute{\nOptional: true,\nComputed: true,\nDefault: int64default.StaticInt64(80),\n}",
+			"replicas": "schema.Int64Attribute{\nOptional: true,\nComputed: true,\nPlanModifiers: []planmodifier.Int64{\nint64planmodifier.RequiresReplace(),\nint64planmodifier.UseStateForUnknown(),\n},\n}",
+			"tags":     "schema.SetAttribute{\nElementType: types.StringType,\nOptional: true,\n}",
+		},
+		Blocks: map[string]string{
+			"nested": "schema.ListNestedBlock{\nNestedObject: schema.NestedBlockObject{\nAttributes: map[string]schema.Attribute{\n\"phase\": schema.StringAttribute{\nRequired: true,\n},\n},\n},\nValidators: []validator.List{\nlistvalidator.SizeAtMost(1),\n},\nPlanModifiers: []planmodifier.List{\nlistplanmodifier.RequiresReplace(),\n},\n}",
+		},
+		Imports: []string{
+			"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator",
+			"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default",
+			"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier",
+			"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier",
+			"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier",
+			"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier",
+			"github.com/hashicorp/terraform-plugin-framework/schema/validator",
+			"github.com/hashicorp/terraform-plugin-framework/types",
+		},
+	}
+	if !reflect.DeepEqual(fs, expectedSchema) {
+		t.Fatalf("Expected: %#v\n\nGiven: %#v\n", expectedSchema, fs)
+	}
+
+	fs, err = RenderFrameworkDataSourceSchema(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedAttrs := map[string]string{
+		"name":     "schema.StringAttribute{\nRequired: true,\n}",
+		"port":     "schema.Int64Attribute{\nOptional: true,\n}",
+		"replicas": "schema.Int64Attribute{\nOptional: true,\nComputed: true,\n}",
+		"tags":     "schema.SetAttribute{\nElementType: types.StringType,\nOptional: true,\n}",
+	}
+	if !reflect.DeepEqual(fs.Attributes, expectedAttrs) {
+		t.Fatalf("Expected: %#v\n\nGiven: %#v\n", expectedAttrs, fs.Attributes)
+	}
+}
+
+func TestWithIDField(t *testing.T) {
+	fields := []*Field{
+		{Name: "id", Schema: &schema.Schema{Type: schema.TypeString, Required: true}},
+	}
+	if given := WithIDField(fields); !reflect.DeepEqual(given, fields) {
+		t.Fatalf("Expected existing id to be kept, given: %#v", given)
+	}
+}
@@ -0,0 +1,37 @@
+package schemagen
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// HelperFuncSet collects HelperFuncs referred to by the built schema,
+// embedded by SchemaGenerator as well as generators building schema
+// from other sources (e.g. openapi)
+type HelperFuncSet struct {
+	funcs map[string]string
+}
+
+// DeclareHelperFunc returns the name to refer to from SchemaFuncs
+func (hs *HelperFuncSet) DeclareHelperFunc(funcName string) string {
+	if hs.funcs == nil {
+		hs.funcs = make(map[string]string, 0)
+	}
+	hs.funcs[funcName] = HelperFuncs[funcName]
+	return funcName
+}
+
+// SchemaFunctions returns helper functions the built schema refers to
+func (hs *HelperFuncSet) SchemaFunctions() map[string]string {
+	m := make(map[string]string, len(hs.funcs))
+	for name, code := range hs.funcs {
+		m[name] = code
+	}
+	return m
+}
+
+// AppendDescription adds a sentence, e.g. listing possible values
+func AppendDescription(s *schema.Schema, text string) {
+	s.Description = strings.TrimSpace(s.Description + " " + text)
+}
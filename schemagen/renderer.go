@@ -0,0 +1,142 @@
+package schemagen
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// RenderFields renders fields as entries of map[string]*schema.Schema
+func RenderFields(fields []*Field) (map[string]string, error) {
+	m := make(map[string]string, len(fields))
+	for _, f := range fields {
+		code, err := RenderField(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", f.Name, err)
+		}
+		m[f.Name] = code
+	}
+	return m, nil
+}
+
+func RenderField(f *Field) (string, error) {
+	return renderField(f, false)
+}
+
+func renderField(f *Field, isNested bool) (string, error) {
+	s := *f.Schema
+	if f.Elem != nil {
+		elem, err := renderField(f.Elem, true)
+		if err != nil {
+			return "", err
+		}
+		s.Elem = elem
+	}
+	if f.Block != nil {
+		elem, err := renderBlock(f.Block)
+		if err != nil {
+			return "", err
+		}
+		s.Elem = elem
+	}
+	return schemaCode(&s, f.Funcs, isNested)
+}
+
+func renderBlock(b *Block) (string, error) {
+	if b.FuncName != "" {
+		return b.FuncName + "()", nil
+	}
+	return renderResource(b)
+}
+
+func renderResource(b *Block) (string, error) {
+	code := "&schema.Resource{\nSchema: map[string]*schema.Schema{\n"
+	for _, f := range b.Fields {
+		fieldCode, err := RenderField(f)
+		if err != nil {
+			return "", fmt.Errorf("%s: %s", f.Name, err)
+		}
+		code += fmt.Sprintf("%q: %s,\n", f.Name, fieldCode)
+	}
+	return code + "},\n}", nil
+}
+
+func renderSchemaFunc(funcName string, b *Block) (string, error) {
+	elem, err := renderResource(b)
+	if err != nil {
+		return "", err
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err = schemaFuncTemplate.Execute(buf, struct {
+		FuncName string
+		Elem     string
+	}{
+		FuncName: funcName,
+		Elem:     elem,
+	})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func schemaCode(s *schema.Schema, funcs *SchemaFuncs, isNested bool) (string, error) {
+	buf := bytes.NewBuffer([]byte{})
+	err := schemaTemplate.Execute(buf, struct {
+		Schema   *schema.Schema
+		Funcs    *SchemaFuncs
+		IsNested bool
+		Default  string
+	}{
+		Schema:   s,
+		Funcs:    funcs,
+		IsNested: isNested,
+		Default:  defaultCode(s.Default),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// Float defaults keep the decimal point, so these aren't mistaken for int
+func defaultCode(v interface{}) string {
+	switch d := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		code := strconv.FormatFloat(d, 'g', -1, 64)
+		if !strings.ContainsAny(code, ".e") {
+			code += ".0"
+		}
+		return code
+	}
+	return fmt.Sprintf("%#v", v)
+}
+
+var schemaTemplate = template.Must(template.New("schema").Parse(`{{if .IsNested}}&schema.Schema{{end}}{{"{"}}{{if not .IsNested}}
+{{end}}Type: schema.{{.Schema.Type}},{{if ne .Schema.Description ""}}
+Description: {{printf "%q" .Schema.Description}},{{end}}{{if .Schema.Required}}
+Required: {{.Schema.Required}},{{end}}{{if .Schema.Optional}}
+Optional: {{.Schema.Optional}},{{end}}{{if .Schema.ForceNew}}
+ForceNew: {{.Schema.ForceNew}},{{end}}{{if .Schema.Computed}}
+Computed: {{.Schema.Computed}},{{end}}{{if .Default}}
+Default: {{.Default}},{{end}}{{if gt .Schema.MaxItems 0}}
+MaxItems: {{.Schema.MaxItems}},{{end}}{{if .Schema.ConflictsWith}}
+ConflictsWith: {{printf "%#v" .Schema.ConflictsWith}},{{end}}{{if .Schema.Elem}}
+Elem: {{.Schema.Elem}},{{end}}{{if ne .Funcs.Set ""}}{{if not .IsNested}}
+{{end}}Set: {{.Funcs.Set}},{{end}}{{if ne .Funcs.StateFunc ""}}{{if not .IsNested}}
+{{end}}StateFunc: {{.Funcs.StateFunc}},{{end}}{{if ne .Funcs.DiffSuppressFunc ""}}{{if not .IsNested}}
+{{end}}DiffSuppressFunc: {{.Funcs.DiffSuppressFunc}},{{end}}{{if ne .Funcs.ValidateFunc ""}}{{if not .IsNested}}
+{{end}}ValidateFunc: {{.Funcs.ValidateFunc}},{{end}}{{if not .IsNested}}
+{{end}}{{"}"}}`))
+
+var schemaFuncTemplate = template.Must(template.New("schema-func").Parse(`func {{.FuncName}}() *schema.Resource {
+return {{.Elem}}
+}`))
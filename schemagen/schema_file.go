@@ -0,0 +1,76 @@
+package schemagen
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// SchemaFile is a standalone file declaring the schema (as a variable)
+// together with helper functions it refers to, see HelperFuncSet
+type SchemaFile struct {
+	PkgName      string
+	VariableName string
+	Backend      Backend
+
+	// Rendered fields, see RenderFields
+	Fields    map[string]string
+	Functions map[string]string
+}
+
+func (sf *SchemaFile) Write(w io.Writer) error {
+	return schemaFileTemplate.Execute(w, sf)
+}
+
+func (sf *SchemaFile) SchemaImportPath() string {
+	return sf.Backend.SchemaImportPath()
+}
+
+func (sf *SchemaFile) ValidationImportPath() string {
+	for _, code := range sf.Fields {
+		if strings.Contains(code, "validation.") {
+			return sf.Backend.ValidationImportPath()
+		}
+	}
+	return ""
+}
+
+// Standard library packages helper functions refer to
+func (sf *SchemaFile) StdImports() []string {
+	pkgs := map[string]string{"encoding/base64": "base64.", "encoding/json": "json.", "fmt": "fmt.", "time": "time."}
+	imports := make([]string, 0)
+	for path, prefix := range pkgs {
+		for _, code := range sf.Functions {
+			if strings.Contains(code, prefix) {
+				imports = append(imports, path)
+				break
+			}
+		}
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+var schemaFileTemplate = template.Must(template.New("schema-file").Parse(`package {{.PkgName}}
+
+import (
+{{- range .StdImports}}
+	"{{.}}"
+{{- end}}
+{{- if .StdImports}}
+{{end}}
+	"{{.SchemaImportPath}}"
+{{- with .ValidationImportPath}}
+	"{{.}}"
+{{- end}}
+)
+
+var {{.VariableName}} = map[string]*schema.Schema{
+{{- range $name, $schema := .Fields}}
+	"{{ $name }}": {{ $schema }},
+{{- end}}
+}
+{{range $name, $definition := .Functions}}
+{{ $definition }}
+{{end}}`))
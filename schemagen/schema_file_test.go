@@ -0,0 +1,57 @@
+package schemagen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestSchemaFile_helperFuncs(t *testing.T) {
+	hs := &HelperFuncSet{}
+	s := &schema.Schema{Type: schema.TypeString, Optional: true, Description: "When to start."}
+	AppendDescription(s, "Defaults to `now`.")
+	fields := []*Field{
+		{
+			Name:   "start_time",
+			Schema: s,
+			Funcs:  &SchemaFuncs{ValidateFunc: hs.DeclareHelperFunc("validateRFC3339Time")},
+		},
+		{
+			Name:   "mode",
+			Schema: &schema.Schema{Type: schema.TypeString, Required: true},
+			Funcs:  &SchemaFuncs{ValidateFunc: `validation.StringInSlice([]string{"fast"}, false)`},
+		},
+	}
+	rendered, err := RenderFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	sf := &SchemaFile{
+		PkgName:      "acme",
+		VariableName: "jobSchema",
+		Backend:      SDKv2,
+		Fields:       rendered,
+		Functions:    hs.SchemaFunctions(),
+	}
+	if err := sf.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "package acme\n\nimport (\n" +
+		"\t\"fmt\"\n\t\"time\"\n\n" +
+		"\t\"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema\"\n" +
+		"\t\"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation\"\n" +
+		")\n\nvar jobSchema = map[string]*schema.Schema{\n" +
+		"\t\"mode\": " + rendered["mode"] + ",\n" +
+		"\t\"start_time\": " + rendered["start_time"] + ",\n" +
+		"}\n\n" + validateRFC3339TimeFunc + "\n"
+	if buf.String() != expected {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", expected, buf.String())
+	}
+	if s.Description != "When to start. Defaults to `now`." {
+		t.Fatalf("Unexpected description: %q", s.Description)
+	}
+}
@@ -1,12 +1,12 @@
 package schemagen
 
 import (
-	"bytes"
 	"fmt"
 	"log"
 	"reflect"
 	"sort"
-	"text/template"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	u "github.com/radeksimko/terraform-gen/internal/util"
@@ -16,65 +16,202 @@ type getDocsFunc func(iface interface{}, sf *reflect.StructField) string
 type filterFunc func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool)
 
 type SchemaGenerator struct {
+	HelperFuncSet
+
+	// Both are optional, all fields are undocumented
+	// and their attributes inferred (see InferAttributesFilter) by default
 	DocsFunc   getDocsFunc
 	FilterFunc filterFunc
+
+	// Embedded (anonymous) struct fields are promoted to the parent
+	// the same way encoding/json does it, unless this is set
+	// in which case they become nested blocks named after the type
+	NestEmbeddedStructs bool
+
+	// Nested structs deeper than this are represented
+	// as JSON-encoded strings instead of nested blocks (0 = unlimited)
+	MaxDepth int
+
+	// Each distinct nested struct is lifted into a named function
+	// (e.g. containerSchema()) which is referenced wherever it's used.
+	// These are available via SchemaFunctions() along with
+	// any other helper functions the generated schema refers to
+	SharedSchemaFuncs bool
+
+	// Generate schema for a data source, i.e. every field is Computed
+	// except LookupArguments (and blocks containing them) which are Required
+	DataSource bool
+	// Paths of arguments used to look up the data source, e.g. metadata.0.name
+	LookupArguments []string
+
+	typeStack        []reflect.Type
+	path             []string
+	depth            int
+	declarations     map[string]*schemaFuncDeclaration
+	validationErrors []error
+}
+
+type schemaFuncDeclaration struct {
+	Type     reflect.Type
+	FuncName string
+	Block    *Block
+	// Rendered block at the time of declaration
+	Elem string
 }
 
+// FromStruct renders fields of the given struct as entries of map[string]*schema.Schema
 func (g *SchemaGenerator) FromStruct(iface interface{}) map[string]string {
+	m, err := RenderFields(g.FieldsFromStruct(iface))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return m
+}
+
+// FieldsFromStruct builds the schema tree (sorted by field name)
+// which may be transformed before it's rendered via RenderFields
+func (g *SchemaGenerator) FieldsFromStruct(iface interface{}) []*Field {
+	if g.DocsFunc == nil {
+		g.DocsFunc = noDocs
+	}
+	if g.FilterFunc == nil {
+		g.FilterFunc = InferAttributesFilter
+	}
+
 	rawType := u.DereferencePtrType(reflect.TypeOf(iface))
-	fields := make(map[string]string, 0)
+	fields := make(map[string]*Field, 0)
+	promotedFields := make(map[string]*Field, 0)
+
+	g.typeStack = append(g.typeStack, rawType)
+	defer func() {
+		g.typeStack = g.typeStack[:len(g.typeStack)-1]
+	}()
 
 	for i := 0; i < rawType.NumField(); i++ {
 		sf := rawType.Field(i)
 
-		content, err := g.generateField(sf.Name, sf.Type, iface, &sf, false)
+		if u.IsEmbeddedStruct(&sf) && !g.NestEmbeddedStructs {
+			promoted, err := g.promoteEmbeddedStruct(iface, &sf)
+			if err != nil {
+				log.Printf("ERROR: %s", err)
+				continue
+			}
+			for _, f := range promoted {
+				promotedFields[f.Name] = f
+			}
+			continue
+		}
+
+		name := u.Underscore(sf.Name)
+		g.path = append(g.path, name)
+		f, err := g.generateField(sf.Name, sf.Type, iface, &sf)
+		g.path = g.path[:len(g.path)-1]
 		if err != nil {
 			log.Printf("ERROR: %s", err)
 		} else {
-			fields[u.Underscore(sf.Name)] = content
+			f.Name = name
+			fields[name] = f
+		}
+	}
+
+	// Fields of the parent struct take precedence over promoted ones
+	for k, f := range promotedFields {
+		if _, ok := fields[k]; !ok {
+			fields[k] = f
 		}
 	}
 
-	return fields
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	sorted := make([]*Field, len(names))
+	for i, k := range names {
+		sorted[i] = fields[k]
+	}
+
+	return sorted
+}
+
+func (g *SchemaGenerator) promoteEmbeddedStruct(iface interface{}, sf *reflect.StructField) ([]*Field, error) {
+	structType := u.DereferencePtrType(sf.Type)
+
+	_, ok := g.FilterFunc(iface, sf, structType.Kind(), &schema.Schema{})
+	if !ok {
+		return nil, fmt.Errorf("Skipping %q (filter)", sf.Name)
+	}
+	if err := u.CycleError(g.typeStack, structType); err != nil {
+		path := append(append([]string{}, g.path...), u.Underscore(sf.Name))
+		g.cycleError(path, err)
+		return nil, err
+	}
+
+	return g.FieldsFromStruct(reflect.New(structType).Elem().Interface()), nil
 }
 
-func (g *SchemaGenerator) generateField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField, isNested bool) (string, error) {
+func (g *SchemaGenerator) generateField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField) (*Field, error) {
 	kind := u.DereferencePtrType(sfType).Kind()
-	var comment, setFunc string
+	var comment string
 	s := &schema.Schema{}
+	f := &Field{Schema: s, Funcs: &SchemaFuncs{}}
 
 	if sf != nil {
 		var ok bool
 		kind, ok = g.FilterFunc(iface, sf, kind, s)
 		if !ok {
-			return "", fmt.Errorf("Skipping %q (filter)", sf.Name)
+			return nil, fmt.Errorf("Skipping %q (filter)", sf.Name)
 		}
 		comment = g.DocsFunc(iface, sf)
 	}
 
+	// Unless the filter decided otherwise
+	if kind == u.DereferencePtrType(sfType).Kind() {
+		if conv := u.ConversionForType(sfType); conv != u.NoConversion {
+			g.convertedString(conv, s, f.Funcs)
+			s.Description = comment
+			normalizeDefault(sfName, s)
+			if sf != nil && g.DataSource {
+				g.dataSourceField(s)
+			}
+			if sf != nil {
+				g.validateField(f)
+			}
+			return f, nil
+		}
+	}
+
 	switch kind {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		s.Type = schema.TypeInt
-	case reflect.Float32, reflect.Float64:
-		s.Type = schema.TypeFloat
-	case reflect.String:
-		s.Type = schema.TypeString
-	case reflect.Bool:
-		s.Type = schema.TypeBool
 	case reflect.Slice:
+		elemType := sfType.Elem()
+		isStructElem := u.DereferencePtrType(elemType).Kind() == reflect.Struct &&
+			u.ConversionForType(elemType) == u.NoConversion
+		if g.isDepthExceeded() && u.DereferencePtrType(elemType).Kind() == reflect.Struct {
+			log.Printf("Max depth reached, converting %q to JSON-encoded TypeString", sfName)
+			g.convertedString(u.JSONConversion, s, f.Funcs)
+			break
+		}
+
 		// TODO: TypeList may be more suitable for some situations
 		// TODO: Proper SetFunc may be required for TypeSet
 		s.Type = schema.TypeSet
-		elem, err := g.generateField("", sfType.Elem(), iface, nil, true)
+		if isStructElem {
+			block, err := g.generateBlock(sfName, u.DereferencePtrType(elemType))
+			if err != nil {
+				return nil, fmt.Errorf("Unable to generate Elem for %q: %s", sfName, err)
+			}
+			f.Block = block
+			break
+		}
+
+		elem, err := g.generateField("", elemType, iface, nil)
 		if err != nil {
-			return "", fmt.Errorf("Unable to generate Elem for %q: %s", sfName, err)
+			return nil, fmt.Errorf("Unable to generate Elem for %q: %s", sfName, err)
 		}
-		s.Elem = elem
+		f.Elem = elem
 
-		elemKind := u.DereferencePtrType(sfType.Elem()).Kind()
-		if elemKind == reflect.String {
-			setFunc = "schema.HashString"
+		if u.DereferencePtrType(elemType).Kind() == reflect.String {
+			f.Funcs.Set = "schema.HashString"
 		}
 	case reflect.Map:
 		s.Type = schema.TypeMap
@@ -83,71 +220,311 @@ func (g *SchemaGenerator) generateField(sfName string, sfType reflect.Type, ifac
 		// TODO: Elem(map[string]bool)
 		// TODO: Elem(map[string]float)
 	case reflect.Struct:
-		structType := sfType
-		if structType.Kind() == reflect.Ptr {
-			structType = structType.Elem()
+		if g.isDepthExceeded() {
+			log.Printf("Max depth reached, converting %q to JSON-encoded TypeString", sfName)
+			g.convertedString(u.JSONConversion, s, f.Funcs)
+			break
 		}
 
+		block, err := g.generateBlock(sfName, u.DereferencePtrType(sfType))
+		if err != nil {
+			return nil, err
+		}
 		s.Type = schema.TypeList
 		s.MaxItems = 1
+		f.Block = block
+	default:
+		t, ok := primitiveValueType(kind)
+		if !ok {
+			f := fmt.Sprintf("%s %s\n", sfName, sfType.String())
+			return nil, fmt.Errorf("Unable to process: %s", f)
+		}
+		s.Type = t
+	}
 
-		elem := "&schema.Resource{\nSchema: map[string]*schema.Schema{\n"
+	s.Description = comment
+	normalizeDefault(sfName, s)
+	if sf != nil && g.DataSource {
+		g.dataSourceField(s)
+	}
+	if sf != nil {
+		g.validateField(f)
+	}
 
-		iface := reflect.New(structType).Elem().Interface()
+	return f, nil
+}
 
-		m := g.FromStruct(iface)
-		fieldNames := make([]string, len(m), len(m))
-		i := 0
-		for k, _ := range m {
-			fieldNames[i] = k
-			i++
+func (g *SchemaGenerator) generateBlock(sfName string, structType reflect.Type) (*Block, error) {
+	// Depth limit (if any) stops the recursion before a cycle does
+	if g.MaxDepth == 0 {
+		if err := u.CycleError(g.typeStack, structType); err != nil {
+			g.cycleError(g.path, err)
+			return nil, fmt.Errorf("Unable to process %q: %s", sfName, err)
 		}
-		sort.Strings(fieldNames)
-		for _, k := range fieldNames {
-			elem += fmt.Sprintf("%q: %s,\n", k, m[k])
+	}
+
+	g.depth++
+	block := &Block{
+		Fields: g.FieldsFromStruct(reflect.New(structType).Elem().Interface()),
+	}
+	g.depth--
+
+	if g.SharedSchemaFuncs {
+		funcName, err := g.declareSchemaFunc(structType, sfName, block)
+		if err != nil {
+			return nil, err
 		}
-		elem += "},\n}"
-		if isNested {
-			return elem, nil
+		block.FuncName = funcName
+	}
+	return block, nil
+}
+
+func primitiveValueType(kind reflect.Kind) (schema.ValueType, bool) {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return schema.TypeInt, true
+	case reflect.Float32, reflect.Float64:
+		return schema.TypeFloat, true
+	case reflect.String:
+		return schema.TypeString, true
+	case reflect.Bool:
+		return schema.TypeBool, true
+	}
+	return schema.TypeInvalid, false
+}
+
+// ValidationErrors returns problems found by the SDK's InternalValidate
+// in the generated schema, as well as fields dropped due to a cycle,
+// each prefixed with the field path (e.g. metadata.name)
+func (g *SchemaGenerator) ValidationErrors() []error {
+	return g.validationErrors
+}
+
+// Field is dropped, so the schema is incomplete
+func (g *SchemaGenerator) cycleError(path []string, err error) {
+	g.validationErrors = append(g.validationErrors, fmt.Errorf("%s: %s", strings.Join(path, "."), err))
+}
+
+// Field is validated on its own (nested fields are validated separately)
+func (g *SchemaGenerator) validateField(f *Field) {
+	field := *f.Schema
+	if f.Elem != nil {
+		field.Elem = f.Elem.SDKSchema()
+	}
+	if f.Block != nil {
+		field.Elem = &schema.Resource{Schema: map[string]*schema.Schema{}}
+	}
+
+	name := g.path[len(g.path)-1]
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{name: &field},
+	}
+	if err := r.InternalValidate(nil, true); err != nil {
+		// Errors are prefixed by the field name, nested ones get the full path
+		if len(g.path) > 1 {
+			err = fmt.Errorf("%s.%s", strings.Join(g.path[:len(g.path)-1], "."), err)
 		}
+		log.Printf("WARN: Invalid schema: %s", err)
+		g.validationErrors = append(g.validationErrors, err)
+	}
+}
+
+func (g *SchemaGenerator) dataSourceField(s *schema.Schema) {
+	lookup := g.isLookupArgument()
+	s.Required = lookup
+	s.Optional = false
+	s.ForceNew = false
+	s.Default = nil
+	s.Computed = !lookup
+}
 
-		s.Elem = elem
+// Defaults given as strings (e.g. by tags or docs) are converted to the field's type,
+// invalid ones are dropped
+func normalizeDefault(name string, s *schema.Schema) {
+	str, ok := s.Default.(string)
+	if !ok || s.Type == schema.TypeString {
+		return
+	}
+
+	var err error
+	switch s.Type {
+	case schema.TypeInt:
+		if str == "" {
+			str = "0"
+		}
+		s.Default, err = strconv.Atoi(str)
+	case schema.TypeFloat:
+		if str == "" {
+			str = "0"
+		}
+		s.Default, err = strconv.ParseFloat(str, 64)
+	case schema.TypeBool:
+		if str == "" {
+			str = "false"
+		}
+		s.Default, err = strconv.ParseBool(str)
 	default:
-		f := fmt.Sprintf("%s %s\n", sfName, sfType.String())
-		return "", fmt.Errorf("Unable to process: %s", f)
+		err = fmt.Errorf("%s can't have a default", s.Type)
 	}
+	if err != nil {
+		log.Printf("ERROR: %s: Invalid default %q: %s - SKIPPING", name, str, err)
+		s.Default = nil
+	}
+}
 
-	s.Description = comment
+// Current field is either one of the lookup arguments or a block containing one
+func (g *SchemaGenerator) isLookupArgument() bool {
+	path := strings.Join(g.path, ".")
+	for _, arg := range g.LookupArguments {
+		argPath := lookupArgumentPath(arg)
+		if argPath == path || strings.HasPrefix(argPath, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// List indexes (metadata.0.name) are irrelevant for the schema
+func lookupArgumentPath(arg string) string {
+	parts := make([]string, 0)
+	for _, p := range strings.Split(arg, ".") {
+		if _, err := strconv.Atoi(p); err != nil {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func (g *SchemaGenerator) convertedString(conv u.Conversion, s *schema.Schema, funcs *SchemaFuncs) {
+	s.Type = schema.TypeString
 
-	return schemaCode(s, setFunc, isNested)
+	// Computed-only fields are never validated nor diffed against config
+	if g.DataSource && !g.isLookupArgument() {
+		if conv == u.JSONConversion {
+			funcs.StateFunc = g.DeclareHelperFunc("normalizeJSONString")
+		}
+		return
+	}
+
+	switch conv {
+	case u.JSONConversion:
+		funcs.StateFunc = g.DeclareHelperFunc("normalizeJSONString")
+		funcs.DiffSuppressFunc = g.DeclareHelperFunc("suppressEquivalentJSONDiffs")
+		funcs.ValidateFunc = g.DeclareHelperFunc("validateJSONString")
+	case u.Base64Conversion:
+		funcs.ValidateFunc = g.DeclareHelperFunc("validateBase64")
+	case u.DurationConversion:
+		funcs.ValidateFunc = g.DeclareHelperFunc("validateDuration")
+	case u.TimeConversion:
+		funcs.ValidateFunc = g.DeclareHelperFunc("validateRFC3339Time")
+	}
+}
+
+func (g *SchemaGenerator) SchemaFunctions() map[string]string {
+	m := g.HelperFuncSet.SchemaFunctions()
+	for name, decl := range g.declarations {
+		code, err := renderSchemaFunc(decl.FuncName, decl.Block)
+		if err != nil {
+			log.Fatal(err)
+		}
+		m[name] = code
+	}
+	return m
 }
 
-func schemaCode(s *schema.Schema, setFunc string, isNested bool) (string, error) {
-	buf := bytes.NewBuffer([]byte{})
-	err := schemaTemplate.Execute(buf, struct {
-		Schema   *schema.Schema
-		SetFunc  string
-		IsNested bool
-	}{
-		Schema:   s,
-		SetFunc:  setFunc,
-		IsNested: isNested,
-	})
+func (g *SchemaGenerator) declareSchemaFunc(t reflect.Type, sfName string, block *Block) (string, error) {
+	if g.declarations == nil {
+		g.declarations = make(map[string]*schemaFuncDeclaration, 0)
+	}
+
+	typeName := t.Name()
+	if typeName == "" {
+		typeName = sfName
+	}
+	baseName := u.LowerFirst(typeName) + "Schema"
+
+	elem, err := renderResource(block)
 	if err != nil {
 		return "", err
 	}
 
-	return buf.String(), nil
+	// Same type may be cut differently by MaxDepth
+	// and different types may share the same name
+	funcName := baseName
+	for i := 2; ; i++ {
+		decl, ok := g.declarations[funcName]
+		if !ok {
+			break
+		}
+		if decl.Type == t && decl.Elem == elem {
+			return funcName, nil
+		}
+		funcName = fmt.Sprintf("%s%d", baseName, i)
+	}
+
+	g.declarations[funcName] = &schemaFuncDeclaration{
+		Type:     t,
+		FuncName: funcName,
+		Block:    block,
+		Elem:     elem,
+	}
+	return funcName, nil
+}
+
+func (g *SchemaGenerator) isDepthExceeded() bool {
+	return g.MaxDepth > 0 && g.depth >= g.MaxDepth
+}
+
+const normalizeJSONStringFunc = `func normalizeJSONString(v interface{}) string {
+var obj interface{}
+if err := json.Unmarshal([]byte(v.(string)), &obj); err != nil {
+return v.(string)
+}
+b, _ := json.Marshal(obj)
+return string(b)
+}`
+
+const suppressEquivalentJSONDiffsFunc = `func suppressEquivalentJSONDiffs(k, old, new string, d *schema.ResourceData) bool {
+return normalizeJSONString(old) == normalizeJSONString(new)
+}`
+
+const validateJSONStringFunc = `func validateJSONString(v interface{}, k string) (ws []string, es []error) {
+var obj interface{}
+if err := json.Unmarshal([]byte(v.(string)), &obj); err != nil {
+es = append(es, fmt.Errorf("%q: %s", k, err))
+}
+return
+}`
+
+const validateBase64Func = `func validateBase64(v interface{}, k string) (ws []string, es []error) {
+if _, err := base64.StdEncoding.DecodeString(v.(string)); err != nil {
+es = append(es, fmt.Errorf("%q: %s", k, err))
+}
+return
+}`
+
+const validateDurationFunc = `func validateDuration(v interface{}, k string) (ws []string, es []error) {
+if _, err := time.ParseDuration(v.(string)); err != nil {
+es = append(es, fmt.Errorf("%q: %s", k, err))
+}
+return
+}`
+
+const validateRFC3339TimeFunc = `func validateRFC3339Time(v interface{}, k string) (ws []string, es []error) {
+if _, err := time.Parse(time.RFC3339, v.(string)); err != nil {
+es = append(es, fmt.Errorf("%q: %s", k, err))
 }
+return
+}`
 
-var schemaTemplate = template.Must(template.New("schema").Parse(`{{if .IsNested}}&schema.Schema{{end}}{{"{"}}{{if not .IsNested}}
-{{end}}Type: schema.{{.Schema.Type}},{{if ne .Schema.Description ""}}
-Description: {{printf "%q" .Schema.Description}},{{end}}{{if .Schema.Required}}
-Required: {{.Schema.Required}},{{end}}{{if .Schema.Optional}}
-Optional: {{.Schema.Optional}},{{end}}{{if .Schema.ForceNew}}
-ForceNew: {{.Schema.ForceNew}},{{end}}{{if .Schema.Computed}}
-Computed: {{.Schema.Computed}},{{end}}{{if gt .Schema.MaxItems 0}}
-MaxItems: {{.Schema.MaxItems}},{{end}}{{if .Schema.Elem}}
-Elem: {{.Schema.Elem}},{{end}}{{if ne .SetFunc ""}}{{if not .IsNested}}
-{{end}}Set: {{.SetFunc}},{{end}}{{if not .IsNested}}
-{{end}}{{"}"}}`))
+// HelperFuncs holds the code of helper functions the generated schema may refer to,
+// so that schema built from other sources (e.g. openapi) can be rendered the same way
+var HelperFuncs = map[string]string{
+	"normalizeJSONString":         normalizeJSONStringFunc,
+	"suppressEquivalentJSONDiffs": suppressEquivalentJSONDiffsFunc,
+	"validateBase64":              validateBase64Func,
+	"validateDuration":            validateDurationFunc,
+	"validateJSONString":          validateJSONStringFunc,
+	"validateRFC3339Time":         validateRFC3339TimeFunc,
+}
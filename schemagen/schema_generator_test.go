@@ -1,8 +1,10 @@
 package schemagen
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 )
@@ -254,3 +256,451 @@ func TestGenerateField_sliceOfStructs(t *testing.T) {
 		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
 	}
 }
+
+func TestGenerateField_embeddedStruct(t *testing.T) {
+	type EmbeddedStruct struct {
+		MyInt    int
+		MyString string
+	}
+	type SimpleStruct struct {
+		EmbeddedStruct
+		MyString bool
+		MyBool   bool
+	}
+
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, true
+	}
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF}
+	schema := g.FromStruct(&SimpleStruct{})
+	expectedSchema := map[string]string{
+		"my_int":    "{\nType: schema.TypeInt,\n}",
+		"my_string": "{\nType: schema.TypeBool,\n}",
+		"my_bool":   "{\nType: schema.TypeBool,\n}",
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
+	}
+}
+
+func TestGenerateField_nestedEmbeddedStruct(t *testing.T) {
+	type EmbeddedStruct struct {
+		MyInt    int
+		MyString string
+	}
+	type SimpleStruct struct {
+		*EmbeddedStruct
+		MyBool bool
+	}
+
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, true
+	}
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF, NestEmbeddedStructs: true}
+	schema := g.FromStruct(&SimpleStruct{})
+	expectedSchema := map[string]string{
+		"embedded_struct": "{\nType: schema.TypeList,\nMaxItems: 1,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"my_int\": {\nType: schema.TypeInt,\n},\n\"my_string\": {\nType: schema.TypeString,\n},\n},\n},\n}",
+		"my_bool":         "{\nType: schema.TypeBool,\n}",
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
+	}
+}
+
+type recursiveStruct struct {
+	MyInt int
+	Next  *recursiveStruct
+}
+
+func TestGenerateField_cycle(t *testing.T) {
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, true
+	}
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF}
+	schema := g.FromStruct(&recursiveStruct{})
+	expectedSchema := map[string]string{
+		"my_int": "{\nType: schema.TypeInt,\n}",
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
+	}
+}
+
+func TestGenerateField_cycleError(t *testing.T) {
+	type SimpleStruct struct {
+		Tree recursiveStruct
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		s.Optional = true
+		return k, true
+	}
+
+	g := &SchemaGenerator{FilterFunc: filterF}
+	g.FromStruct(&SimpleStruct{})
+
+	errs := g.ValidationErrors()
+	given := make([]string, len(errs))
+	for i, err := range errs {
+		given[i] = err.Error()
+	}
+	expectedErrors := []string{
+		"tree.next: Cycle detected: schemagen.recursiveStruct -> schemagen.recursiveStruct",
+	}
+	if !reflect.DeepEqual(given, expectedErrors) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedErrors, given)
+	}
+}
+
+type recursiveEmbeddedStruct struct {
+	*recursiveEmbeddedStruct
+	MyInt int
+}
+
+func TestGenerateField_embeddedCycle(t *testing.T) {
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		s.Optional = true
+		return k, true
+	}
+
+	g := &SchemaGenerator{FilterFunc: filterF}
+	schema := g.FromStruct(&recursiveEmbeddedStruct{})
+	expectedSchema := map[string]string{
+		"my_int": "{\nType: schema.TypeInt,\nOptional: true,\n}",
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
+	}
+
+	errs := g.ValidationErrors()
+	given := make([]string, len(errs))
+	for i, err := range errs {
+		given[i] = err.Error()
+	}
+	expectedErrors := []string{
+		"recursive_embedded_struct: Cycle detected: schemagen.recursiveEmbeddedStruct -> schemagen.recursiveEmbeddedStruct",
+	}
+	if !reflect.DeepEqual(given, expectedErrors) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedErrors, given)
+	}
+}
+
+func TestGenerateField_maxDepth(t *testing.T) {
+	type NestedStruct struct {
+		MyInt int
+	}
+	type SimpleStruct struct {
+		Nested      *NestedStruct
+		NestedSlice []NestedStruct
+	}
+	type ParentStruct struct {
+		Simple SimpleStruct
+		MyBool bool
+	}
+
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, true
+	}
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF, MaxDepth: 1}
+	schema := g.FromStruct(&ParentStruct{})
+	expectedSchema := map[string]string{
+		"simple":  "{\nType: schema.TypeList,\nMaxItems: 1,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"nested\": {\nType: schema.TypeString,\nStateFunc: normalizeJSONString,\nDiffSuppressFunc: suppressEquivalentJSONDiffs,\nValidateFunc: validateJSONString,\n},\n\"nested_slice\": {\nType: schema.TypeString,\nStateFunc: normalizeJSONString,\nDiffSuppressFunc: suppressEquivalentJSONDiffs,\nValidateFunc: validateJSONString,\n},\n},\n},\n}",
+		"my_bool": "{\nType: schema.TypeBool,\n}",
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
+	}
+
+	recursiveSchema := g.FromStruct(&recursiveStruct{})
+	expectedRecursiveSchema := map[string]string{
+		"my_int": "{\nType: schema.TypeInt,\n}",
+		"next":   "{\nType: schema.TypeList,\nMaxItems: 1,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"my_int\": {\nType: schema.TypeInt,\n},\n\"next\": {\nType: schema.TypeString,\nStateFunc: normalizeJSONString,\nDiffSuppressFunc: suppressEquivalentJSONDiffs,\nValidateFunc: validateJSONString,\n},\n},\n},\n}",
+	}
+	if !reflect.DeepEqual(recursiveSchema, expectedRecursiveSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedRecursiveSchema, recursiveSchema)
+	}
+}
+
+func TestGenerateField_sharedSchemaFuncs(t *testing.T) {
+	type NestedStruct struct {
+		MyInt    int
+		MyString string
+	}
+	type SimpleStruct struct {
+		Nested      *NestedStruct
+		NestedSlice []NestedStruct
+		MyBool      bool
+	}
+
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, true
+	}
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF, SharedSchemaFuncs: true}
+	schema := g.FromStruct(&SimpleStruct{})
+	expectedSchema := map[string]string{
+		"nested":       "{\nType: schema.TypeList,\nMaxItems: 1,\nElem: nestedStructSchema(),\n}",
+		"nested_slice": "{\nType: schema.TypeSet,\nElem: nestedStructSchema(),\n}",
+		"my_bool":      "{\nType: schema.TypeBool,\n}",
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
+	}
+
+	funcs := g.SchemaFunctions()
+	expectedFuncs := map[string]string{
+		"nestedStructSchema": "func nestedStructSchema() *schema.Resource {\nreturn &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"my_int\": {\nType: schema.TypeInt,\n},\n\"my_string\": {\nType: schema.TypeString,\n},\n},\n}\n}",
+	}
+	if !reflect.DeepEqual(funcs, expectedFuncs) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFuncs, funcs)
+	}
+}
+
+func TestGenerateField_freeFormJSON(t *testing.T) {
+	type SimpleStruct struct {
+		MyInterface  interface{}
+		MyRawMessage json.RawMessage
+		MyMap        map[string]interface{}
+		MyString     string
+	}
+
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, true
+	}
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF}
+	schema := g.FromStruct(&SimpleStruct{})
+	expectedSchema := map[string]string{
+		"my_interface":   "{\nType: schema.TypeString,\nStateFunc: normalizeJSONString,\nDiffSuppressFunc: suppressEquivalentJSONDiffs,\nValidateFunc: validateJSONString,\n}",
+		"my_raw_message": "{\nType: schema.TypeString,\nStateFunc: normalizeJSONString,\nDiffSuppressFunc: suppressEquivalentJSONDiffs,\nValidateFunc: validateJSONString,\n}",
+		"my_map":         "{\nType: schema.TypeString,\nStateFunc: normalizeJSONString,\nDiffSuppressFunc: suppressEquivalentJSONDiffs,\nValidateFunc: validateJSONString,\n}",
+		"my_string":      "{\nType: schema.TypeString,\n}",
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
+	}
+
+	funcs := g.SchemaFunctions()
+	expectedFuncs := map[string]string{
+		"normalizeJSONString":         normalizeJSONStringFunc,
+		"suppressEquivalentJSONDiffs": suppressEquivalentJSONDiffsFunc,
+		"validateJSONString":          validateJSONStringFunc,
+	}
+	if !reflect.DeepEqual(funcs, expectedFuncs) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFuncs, funcs)
+	}
+}
+
+func TestGenerateField_builtinTypes(t *testing.T) {
+	type SimpleStruct struct {
+		MyBytes    []byte
+		MyDuration time.Duration
+		MyTime     *time.Time
+		MyInt64    int64
+	}
+
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, true
+	}
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF}
+	schema := g.FromStruct(&SimpleStruct{})
+	expectedSchema := map[string]string{
+		"my_bytes":    "{\nType: schema.TypeString,\nValidateFunc: validateBase64,\n}",
+		"my_duration": "{\nType: schema.TypeString,\nValidateFunc: validateDuration,\n}",
+		"my_time":     "{\nType: schema.TypeString,\nValidateFunc: validateRFC3339Time,\n}",
+		"my_int64":    "{\nType: schema.TypeInt,\n}",
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
+	}
+
+	funcs := g.SchemaFunctions()
+	expectedFuncs := map[string]string{
+		"validateBase64":      validateBase64Func,
+		"validateDuration":    validateDurationFunc,
+		"validateRFC3339Time": validateRFC3339TimeFunc,
+	}
+	if !reflect.DeepEqual(funcs, expectedFuncs) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFuncs, funcs)
+	}
+}
+
+func TestGenerateField_dataSource(t *testing.T) {
+	type Metadata struct {
+		Name string
+		UID  string
+	}
+	type SimpleStruct struct {
+		Metadata   *Metadata
+		MyDuration time.Duration
+		MyInt      int
+	}
+
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		s.Optional = true
+		return k, true
+	}
+
+	g := &SchemaGenerator{
+		DocsFunc:        docsF,
+		FilterFunc:      filterF,
+		DataSource:      true,
+		LookupArguments: []string{"metadata.0.name"},
+	}
+	schema := g.FromStruct(&SimpleStruct{})
+	expectedSchema := map[string]string{
+		"metadata":    "{\nType: schema.TypeList,\nRequired: true,\nMaxItems: 1,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"name\": {\nType: schema.TypeString,\nRequired: true,\n},\n\"uid\": {\nType: schema.TypeString,\nComputed: true,\n},\n},\n},\n}",
+		"my_duration": "{\nType: schema.TypeString,\nComputed: true,\n}",
+		"my_int":      "{\nType: schema.TypeInt,\nComputed: true,\n}",
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
+	}
+
+	if funcs := g.SchemaFunctions(); len(funcs) != 0 {
+		t.Fatalf("Expected no helper functions, given: %s", funcs)
+	}
+}
+
+func TestGenerateField_inferredAttributes(t *testing.T) {
+	type NestedStruct struct {
+		MyInt int
+	}
+	type SimpleStruct struct {
+		MyInt      int
+		MyString   string `json:"myString,omitempty"`
+		MyBool     *bool
+		MyName     string        `tf:"required,forcenew"`
+		MyID       string        `tf:"computed"`
+		MyZone     *string       `tf:"optional,computed"`
+		MyInternal string        `tf:"-"`
+		Nested     *NestedStruct `json:"nested"`
+	}
+
+	g := &SchemaGenerator{}
+	schema := g.FromStruct(&SimpleStruct{})
+	expectedSchema := map[string]string{
+		"my_int":    "{\nType: schema.TypeInt,\nRequired: true,\n}",
+		"my_string": "{\nType: schema.TypeString,\nOptional: true,\n}",
+		"my_bool":   "{\nType: schema.TypeBool,\nOptional: true,\n}",
+		"my_name":   "{\nType: schema.TypeString,\nRequired: true,\nForceNew: true,\n}",
+		"my_id":     "{\nType: schema.TypeString,\nComputed: true,\n}",
+		"my_zone":   "{\nType: schema.TypeString,\nOptional: true,\nComputed: true,\n}",
+		"nested":    "{\nType: schema.TypeList,\nOptional: true,\nMaxItems: 1,\nElem: &schema.Resource{\nSchema: map[string]*schema.Schema{\n\"my_int\": {\nType: schema.TypeInt,\nRequired: true,\n},\n},\n},\n}",
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
+	}
+
+	if errs := g.ValidationErrors(); len(errs) != 0 {
+		t.Fatalf("Expected no validation errors, given: %s", errs)
+	}
+}
+
+func TestGenerateField_defaults(t *testing.T) {
+	type SimpleStruct struct {
+		MyPort    int      `tf:"default=80"`
+		MyRatio   float64  `tf:"optional,default=0.5"`
+		MyEnabled bool     `tf:"default"`
+		MyName    string   `tf:"default=foo"`
+		MyInvalid int      `tf:"default=many"`
+		MyTags    []string `tf:"default=foo"`
+		MyList    string   `tf:"forcenew,default=a,b"`
+		MyZone    string   `tf:"default,forcenew"`
+		MyID      string   `tf:"required,default=foo"`
+		MyState   string   `tf:"computed,default=x"`
+	}
+
+	g := &SchemaGenerator{}
+	schema := g.FromStruct(&SimpleStruct{})
+	expectedSchema := map[string]string{
+		"my_port":    "{\nType: schema.TypeInt,\nOptional: true,\nDefault: 80,\n}",
+		"my_ratio":   "{\nType: schema.TypeFloat,\nOptional: true,\nDefault: 0.5,\n}",
+		"my_enabled": "{\nType: schema.TypeBool,\nOptional: true,\nDefault: false,\n}",
+		"my_name":    "{\nType: schema.TypeString,\nOptional: true,\nDefault: \"foo\",\n}",
+		"my_invalid": "{\nType: schema.TypeInt,\nOptional: true,\n}",
+		"my_tags":    "{\nType: schema.TypeSet,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\nSet: schema.HashString,\n}",
+		"my_list":    "{\nType: schema.TypeString,\nOptional: true,\nForceNew: true,\nDefault: \"a,b\",\n}",
+		"my_zone":    "{\nType: schema.TypeString,\nOptional: true,\nForceNew: true,\nDefault: \"\",\n}",
+		"my_id":      "{\nType: schema.TypeString,\nRequired: true,\n}",
+		"my_state":   "{\nType: schema.TypeString,\nComputed: true,\n}",
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
+	}
+
+	if errs := g.ValidationErrors(); len(errs) != 0 {
+		t.Fatalf("Expected no validation errors, given: %s", errs)
+	}
+}
+
+func TestGenerateField_validation(t *testing.T) {
+	type NestedStruct struct {
+		NestedName string `tf:"optional,required"`
+	}
+	type SimpleStruct struct {
+		MyInt  int
+		MyMap  map[string]string
+		MyID   string `tf:"required,computed"`
+		MyList []string
+		Nested *NestedStruct
+	}
+
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		switch sf.Name {
+		case "MyInt":
+		case "MyMap":
+			s.Optional = true
+			s.MaxItems = 1
+		default:
+			InferAttributes(sf, s)
+		}
+		return k, true
+	}
+
+	g := &SchemaGenerator{FilterFunc: filterF}
+	g.FromStruct(&SimpleStruct{})
+
+	errs := g.ValidationErrors()
+	given := make([]string, len(errs))
+	for i, err := range errs {
+		given[i] = err.Error()
+	}
+	expectedErrors := []string{
+		"my_int: One of optional, required, or computed must be set",
+		"my_map: MaxItems and MinItems are only supported on lists or sets",
+		"my_id: Cannot be both Required and Computed",
+		"nested.nested_name: Optional or Required must be set, not both",
+	}
+	if !reflect.DeepEqual(given, expectedErrors) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedErrors, given)
+	}
+}
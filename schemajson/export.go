@@ -0,0 +1,146 @@
+package schemajson
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// FromProvider converts the provider the same way the SDK does
+// when Terraform asks for its schema (see helper/schema/core_schema.go)
+func FromProvider(source string, p *schema.Provider) *ProviderSchemas {
+	ps := &ProviderSchema{
+		Provider:          &Schema{Block: blockFromSchemaMap(p.Schema)},
+		ResourceSchemas:   make(map[string]*Schema, len(p.ResourcesMap)),
+		DataSourceSchemas: make(map[string]*Schema, len(p.DataSourcesMap)),
+	}
+	for name, res := range p.ResourcesMap {
+		ps.ResourceSchemas[name] = FromResource(res)
+	}
+	for name, res := range p.DataSourcesMap {
+		ps.DataSourceSchemas[name] = FromResource(res)
+	}
+
+	return &ProviderSchemas{
+		FormatVersion: FormatVersion,
+		Schemas:       map[string]*ProviderSchema{source: ps},
+	}
+}
+
+// FromResource converts a top-level resource or data source,
+// including the implicit id attribute
+func FromResource(res *schema.Resource) *Schema {
+	block := blockFromResource(res)
+	if _, ok := block.Attributes["id"]; !ok {
+		block.Attributes["id"] = &Attribute{
+			Type:            "string",
+			DescriptionKind: "plain",
+			Optional:        true,
+			Computed:        true,
+		}
+	}
+	return &Schema{
+		Version: res.SchemaVersion,
+		Block:   block,
+	}
+}
+
+func blockFromResource(res *schema.Resource) *Block {
+	block := blockFromSchemaMap(res.Schema)
+	block.Deprecated = res.DeprecationMessage != ""
+	return block
+}
+
+func blockFromSchemaMap(m map[string]*schema.Schema) *Block {
+	block := &Block{
+		Attributes:      make(map[string]*Attribute, 0),
+		BlockTypes:      make(map[string]*BlockType, 0),
+		DescriptionKind: "plain",
+	}
+	for name, s := range m {
+		if res, ok := s.Elem.(*schema.Resource); ok && isBlock(s) {
+			block.BlockTypes[name] = blockType(s, res)
+			continue
+		}
+		block.Attributes[name] = attribute(s)
+	}
+	return block
+}
+
+// Computed-only nested resources are attributes (of object type),
+// so are maps, which can't have nested blocks
+func isBlock(s *schema.Schema) bool {
+	if s.Type == schema.TypeMap {
+		return false
+	}
+	return !s.Computed || s.Optional
+}
+
+func blockType(s *schema.Schema, res *schema.Resource) *BlockType {
+	bt := &BlockType{
+		NestingMode: "list",
+		Block:       blockFromResource(res),
+		MinItems:    s.MinItems,
+		MaxItems:    s.MaxItems,
+	}
+	if s.Type == schema.TypeSet {
+		bt.NestingMode = "set"
+	}
+	if s.Required && s.MinItems == 0 {
+		bt.MinItems = 1
+	}
+	return bt
+}
+
+// Required fields with DefaultFunc are in fact optional
+func attribute(s *schema.Schema) *Attribute {
+	a := &Attribute{
+		Type:            impliedType(s),
+		Description:     s.Description,
+		DescriptionKind: "plain",
+		Deprecated:      s.Deprecated != "",
+		Required:        s.Required,
+		Optional:        s.Optional,
+		Computed:        s.Computed,
+		Sensitive:       s.Sensitive,
+	}
+	if s.Required && s.DefaultFunc != nil {
+		a.Required = false
+		a.Optional = true
+	}
+	return a
+}
+
+func impliedType(s *schema.Schema) interface{} {
+	switch s.Type {
+	case schema.TypeBool:
+		return "bool"
+	case schema.TypeInt, schema.TypeFloat:
+		return "number"
+	case schema.TypeList, schema.TypeSet, schema.TypeMap:
+		collection := map[schema.ValueType]string{
+			schema.TypeList: "list",
+			schema.TypeSet:  "set",
+			schema.TypeMap:  "map",
+		}[s.Type]
+
+		switch elem := s.Elem.(type) {
+		case *schema.Schema:
+			return []interface{}{collection, impliedType(elem)}
+		case *schema.Resource:
+			// Maps of resources were only ever supported as map of strings
+			if s.Type == schema.TypeMap {
+				return []interface{}{collection, "string"}
+			}
+			return []interface{}{collection, objectType(elem)}
+		}
+		return []interface{}{collection, "string"}
+	}
+	return "string"
+}
+
+func objectType(res *schema.Resource) interface{} {
+	attrs := make(map[string]interface{}, len(res.Schema))
+	for name, s := range res.Schema {
+		attrs[name] = impliedType(s)
+	}
+	return []interface{}{"object", attrs}
+}
@@ -0,0 +1,127 @@
+package schemajson
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestFromProvider(t *testing.T) {
+	p := &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"token": {
+				Type:        schema.TypeString,
+				Description: "API token",
+				Optional:    true,
+				Sensitive:   true,
+			},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"cattle_cow": {
+				SchemaVersion: 1,
+				Schema: map[string]*schema.Schema{
+					"name": {Type: schema.TypeString, Required: true},
+					"age":  {Type: schema.TypeInt, Optional: true, Deprecated: "Use born_at instead"},
+					"tags": {
+						Type:     schema.TypeSet,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+					"labels": {Type: schema.TypeMap, Optional: true},
+					"owner": {
+						Type:     schema.TypeList,
+						Required: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {Type: schema.TypeString, Optional: true},
+							},
+						},
+					},
+					"status": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"phase": {Type: schema.TypeString, Computed: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	if err := FromProvider("registry.terraform.io/acme/cattle", p).Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOutput := `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/acme/cattle": {
+      "provider": {
+        "version": 0,
+        "block": {
+          "attributes": {
+            "token": {"type": "string", "description": "API token", "description_kind": "plain", "optional": true, "sensitive": true}
+          },
+          "description_kind": "plain"
+        }
+      },
+      "resource_schemas": {
+        "cattle_cow": {
+          "version": 1,
+          "block": {
+            "attributes": {
+              "age": {"type": "number", "description_kind": "plain", "deprecated": true, "optional": true},
+              "id": {"type": "string", "description_kind": "plain", "optional": true, "computed": true},
+              "labels": {"type": ["map", "string"], "description_kind": "plain", "optional": true},
+              "name": {"type": "string", "description_kind": "plain", "required": true},
+              "status": {"type": ["list", ["object", {"phase": "string"}]], "description_kind": "plain", "computed": true},
+              "tags": {"type": ["set", "string"], "description_kind": "plain", "optional": true}
+            },
+            "block_types": {
+              "owner": {
+                "nesting_mode": "list",
+                "block": {
+                  "attributes": {
+                    "name": {"type": "string", "description_kind": "plain", "optional": true}
+                  },
+                  "description_kind": "plain"
+                },
+                "min_items": 1,
+                "max_items": 1
+              }
+            },
+            "description_kind": "plain"
+          }
+        }
+      }
+    }
+  }
+}`
+	var output, expected interface{}
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(expectedOutput), &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, buf.String())
+	}
+
+	ps, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := ps.Schemas["registry.terraform.io/acme/cattle"].ResourceSchemas["cattle_cow"].Block.BlockTypes["owner"]
+	if owner.MaxItems != 1 || owner.Block.Attributes["name"].Type != "string" {
+		t.Fatalf("Unexpected owner block: %#v", owner)
+	}
+}
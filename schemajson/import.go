@@ -0,0 +1,156 @@
+package schemajson
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// SDKResource converts the schema back to *schema.Resource, e.g. for docsgen,
+// functions are left out as JSON carries no behaviour and numbers become TypeFloat
+func (s *Schema) SDKResource() (*schema.Resource, error) {
+	if s.Block == nil {
+		return nil, fmt.Errorf("No block found")
+	}
+	res, err := s.Block.SDKResource()
+	if err != nil {
+		return nil, err
+	}
+	res.SchemaVersion = s.Version
+	return res, nil
+}
+
+func (b *Block) SDKResource() (*schema.Resource, error) {
+	m := make(map[string]*schema.Schema, len(b.Attributes)+len(b.BlockTypes))
+	for name, a := range b.Attributes {
+		s, err := a.sdkSchema()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+		m[name] = s
+	}
+	for name, bt := range b.BlockTypes {
+		s, err := bt.sdkSchema()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+		m[name] = s
+	}
+
+	res := &schema.Resource{Schema: m}
+	if b.Deprecated {
+		res.DeprecationMessage = "Deprecated"
+	}
+	return res, nil
+}
+
+func (a *Attribute) sdkSchema() (*schema.Schema, error) {
+	s, err := schemaFromType(a.Type)
+	if err != nil {
+		return nil, err
+	}
+	s.Description = a.Description
+	s.Required = a.Required
+	s.Optional = a.Optional
+	s.Computed = a.Computed
+	s.Sensitive = a.Sensitive
+	if a.Deprecated {
+		s.Deprecated = "Deprecated"
+	}
+	return s, nil
+}
+
+// Nested blocks are required if these must appear at least once
+func (bt *BlockType) sdkSchema() (*schema.Schema, error) {
+	res, err := bt.Block.SDKResource()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &schema.Schema{
+		Type:     schema.TypeList,
+		Elem:     res,
+		MinItems: bt.MinItems,
+		MaxItems: bt.MaxItems,
+		Optional: bt.MinItems == 0,
+		Required: bt.MinItems > 0,
+	}
+	switch bt.NestingMode {
+	case "list", "map":
+	case "set":
+		s.Type = schema.TypeSet
+	case "single", "group":
+		s.MaxItems = 1
+	default:
+		return nil, fmt.Errorf("Unknown nesting mode %q", bt.NestingMode)
+	}
+	return s, nil
+}
+
+// Type constraints as decoded from JSON, e.g. "string" or ["list","string"]
+func schemaFromType(t interface{}) (*schema.Schema, error) {
+	switch typ := t.(type) {
+	case string:
+		switch typ {
+		case "string", "dynamic":
+			return &schema.Schema{Type: schema.TypeString}, nil
+		case "number":
+			return &schema.Schema{Type: schema.TypeFloat}, nil
+		case "bool":
+			return &schema.Schema{Type: schema.TypeBool}, nil
+		}
+	case []interface{}:
+		if len(typ) != 2 {
+			break
+		}
+		collection, _ := typ[0].(string)
+		if collection == "object" {
+			attrs, ok := typ[1].(map[string]interface{})
+			if !ok {
+				break
+			}
+			return objectSchema(attrs)
+		}
+
+		s := &schema.Schema{}
+		switch collection {
+		case "list":
+			s.Type = schema.TypeList
+		case "set":
+			s.Type = schema.TypeSet
+		case "map":
+			s.Type = schema.TypeMap
+		default:
+			return nil, fmt.Errorf("Unsupported type %q", collection)
+		}
+		elem, err := schemaFromType(typ[1])
+		if err != nil {
+			return nil, err
+		}
+		s.Elem = elem
+		// Objects are nested resources (with computed fields)
+		if res, ok := elem.Elem.(*schema.Resource); ok && elem.Type == schema.TypeList && elem.MaxItems == 1 {
+			s.Elem = res
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("Unsupported type %#v", t)
+}
+
+// A single object is represented as a list with MaxItems: 1
+func objectSchema(attrs map[string]interface{}) (*schema.Schema, error) {
+	m := make(map[string]*schema.Schema, len(attrs))
+	for name, t := range attrs {
+		s, err := schemaFromType(t)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+		s.Computed = true
+		m[name] = s
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		MaxItems: 1,
+		Elem:     &schema.Resource{Schema: m},
+	}, nil
+}
@@ -0,0 +1,76 @@
+package schemajson
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+)
+
+// FormatVersion of the terraform providers schema -json output
+const FormatVersion = "1.0"
+
+// ProviderSchemas mirrors the output of terraform providers schema -json
+type ProviderSchemas struct {
+	FormatVersion string `json:"format_version"`
+	// Keyed by provider source address, e.g. registry.terraform.io/hashicorp/kubernetes
+	Schemas map[string]*ProviderSchema `json:"provider_schemas,omitempty"`
+}
+
+type ProviderSchema struct {
+	Provider          *Schema            `json:"provider,omitempty"`
+	ResourceSchemas   map[string]*Schema `json:"resource_schemas,omitempty"`
+	DataSourceSchemas map[string]*Schema `json:"data_source_schemas,omitempty"`
+}
+
+type Schema struct {
+	Version int    `json:"version"`
+	Block   *Block `json:"block,omitempty"`
+}
+
+type Block struct {
+	Attributes      map[string]*Attribute `json:"attributes,omitempty"`
+	BlockTypes      map[string]*BlockType `json:"block_types,omitempty"`
+	Description     string                `json:"description,omitempty"`
+	DescriptionKind string                `json:"description_kind,omitempty"`
+	Deprecated      bool                  `json:"deprecated,omitempty"`
+}
+
+type Attribute struct {
+	// Type constraint in JSON, e.g. "string" or ["list","string"]
+	Type            interface{} `json:"type"`
+	Description     string      `json:"description,omitempty"`
+	DescriptionKind string      `json:"description_kind,omitempty"`
+	Deprecated      bool        `json:"deprecated,omitempty"`
+	Required        bool        `json:"required,omitempty"`
+	Optional        bool        `json:"optional,omitempty"`
+	Computed        bool        `json:"computed,omitempty"`
+	Sensitive       bool        `json:"sensitive,omitempty"`
+}
+
+type BlockType struct {
+	// list, set, single or map
+	NestingMode string `json:"nesting_mode"`
+	Block       *Block `json:"block"`
+	MinItems    int    `json:"min_items,omitempty"`
+	MaxItems    int    `json:"max_items,omitempty"`
+}
+
+func (ps *ProviderSchemas) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(ps)
+}
+
+func Parse(b []byte) (*ProviderSchemas, error) {
+	ps := &ProviderSchemas{}
+	if err := json.Unmarshal(b, ps); err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+func LoadFile(path string) (*ProviderSchemas, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}